@@ -1,9 +1,12 @@
 package analyzer
 
 import (
+	"fmt"
+	"go/ast"
+	"go/token"
 	"go/types"
-	"log"
 	"sort"
+	"time"
 
 	"github.com/jakebailey/plugingen/typesext"
 	"golang.org/x/tools/go/types/typeutil"
@@ -11,23 +14,66 @@ import (
 
 type Analyzer struct {
 	allowError bool
+	embedded   bool
+	fset       *token.FileSet
+	files      []*ast.File
 
-	done       map[string]*Interface
-	interfaces []*Interface
+	done        map[string]*Interface
+	interfaces  []*Interface
+	diagnostics []Diagnostic
 
 	cache typeutil.MethodSetCache
 }
 
-func NewAnalyzer(allowError bool) *Analyzer {
+// NewAnalyzer creates a new Analyzer. files are searched for the
+// declarations of analyzed interfaces to read their directives.
+func NewAnalyzer(allowError bool, fset *token.FileSet, files []*ast.File) *Analyzer {
 	return &Analyzer{
 		allowError: allowError,
+		fset:       fset,
+		files:      files,
 		done:       map[string]*Interface{},
 	}
 }
 
+// SetEmbedded sets whether named interfaces embedded in analyzed interfaces
+// are analyzed too, and listed in Interface.Embeds, so that code for their
+// methods can be shared by each interface embedding them.
+func (a *Analyzer) SetEmbedded(embedded bool) {
+	a.embedded = embedded
+}
+
 type Interface struct {
-	Typ      types.Type
-	Methods  []*Method
+	Typ     types.Type
+	Methods []*Method
+
+	// Optional lists the interfaces which implementations of the interface
+	// may also implement, set with the optional directive. Hosts can discover
+	// which of them a plugin implements at runtime.
+	Optional []*Interface
+
+	// Embeds lists the named interfaces embedded in the interface, if the
+	// Analyzer was set to analyze them with SetEmbedded. Their methods are
+	// also listed in Methods.
+	Embeds []*Interface
+
+	// Name overrides the name used for generated types, if set.
+	Name string
+
+	// Doc is the text of the interface's doc comment, without directives.
+	Doc string
+
+	// AllowError is true if errors should not be wrapped with
+	// plugin.BasicError by default.
+	AllowError bool
+
+	// Timeout is the default timeout for the interface's methods.
+	Timeout time.Duration
+
+	// TopLevel is true if the interface was passed to AnalyzeAll, rather
+	// than found as a parameter of another interface.
+	TopLevel bool
+
 	sortName string
 }
 
@@ -36,6 +82,29 @@ type Method struct {
 	Params   []*Var
 	Results  []*Var
 	Variadic bool
+
+	// Doc is the text of the method's doc comment, without directives.
+	Doc string
+
+	// AllowError is true if errors should not be wrapped with
+	// plugin.BasicError.
+	AllowError bool
+
+	// Skip is true if the method should not be callable over RPC.
+	Skip bool
+
+	// Oneway is true if callers should not wait for the method to complete.
+	Oneway bool
+
+	// Timeout is the maximum time to wait for a call, or zero for no limit.
+	Timeout time.Duration
+
+	// Retain is true if brokered interface parameters should remain usable
+	// after the method returns.
+	Retain bool
+
+	// Idempotent is true if the method may be retried after a failure.
+	Idempotent bool
 }
 
 type Var struct {
@@ -44,9 +113,12 @@ type Var struct {
 	IFace *Interface
 }
 
-func (a *Analyzer) AnalyzeAll(ts []types.Type) []*Interface {
+// AnalyzeAll analyzes the given interface types, along with any interfaces
+// they use as parameters. If any error diagnostics are reported, the returned
+// error is ErrUnsupported.
+func (a *Analyzer) AnalyzeAll(ts []types.Type) ([]*Interface, []Diagnostic, error) {
 	for _, t := range ts {
-		a.analyze(t)
+		a.analyze(t).TopLevel = true
 	}
 
 	sort.Slice(a.interfaces, func(i, j int) bool {
@@ -56,14 +128,36 @@ func (a *Analyzer) AnalyzeAll(ts []types.Type) []*Interface {
 	ret := a.interfaces
 	a.interfaces = nil
 
+	diags := a.diagnostics
+	a.diagnostics = nil
+
 	for k := range a.done {
 		delete(a.done, k)
 	}
 
-	return ret
+	for _, d := range diags {
+		if d.Severity == Error {
+			return ret, diags, ErrUnsupported
+		}
+	}
+
+	return ret, diags, nil
+}
+
+func (a *Analyzer) report(severity Severity, pos token.Pos, method string, param, result int, format string, args ...interface{}) {
+	a.diagnostics = append(a.diagnostics, Diagnostic{
+		Severity: severity,
+		Pos:      a.fset.Position(pos),
+		Method:   method,
+		Param:    param,
+		Result:   result,
+		Message:  fmt.Sprintf(format, args...),
+	})
 }
 
 func (a *Analyzer) analyze(t types.Type) *Interface {
+	// Aliases of an interface are the same interface, and are named after it.
+	t = types.Unalias(t)
 	typeString := t.String()
 
 	if iface, ok := a.done[typeString]; ok {
@@ -71,16 +165,38 @@ func (a *Analyzer) analyze(t types.Type) *Interface {
 	}
 
 	iface := &Interface{
-		Typ:      t,
-		sortName: typeString,
+		Typ:        t,
+		AllowError: a.allowError,
+		sortName:   typeString,
 	}
 
 	a.done[typeString] = iface
 
+	if named, ok := t.(*types.Named); ok {
+		doc := a.typeDoc(named.Obj().Pos())
+		iface.Doc = doc.Text()
+		a.interfaceDirectives(iface, named, doc)
+	}
+
+	if a.embedded {
+		u := t.Underlying().(*types.Interface)
+		for i := 0; i < u.NumEmbeddeds(); i++ {
+			// Only named interfaces from packages can be shared; error and
+			// constraints such as comparable are left to the embedding
+			// interface.
+			named, ok := types.Unalias(u.EmbeddedType(i)).(*types.Named)
+			if !ok || named.Obj().Pkg() == nil || !types.IsInterface(named) {
+				continue
+			}
+			iface.Embeds = append(iface.Embeds, a.analyze(named))
+		}
+	}
+
 	for _, sel := range typeutil.IntuitiveMethodSet(t, &a.cache) {
 		o := sel.Obj()
 
 		methodName := o.Name()
+		qualName := typeString + "." + methodName
 		sig := o.Type().(*types.Signature)
 
 		params := tupleToSlice(sig.Params())
@@ -88,20 +204,48 @@ func (a *Analyzer) analyze(t types.Type) *Interface {
 		variadic := sig.Variadic()
 
 		method := &Method{
-			Name:     methodName,
-			Params:   make([]*Var, 0, len(params)),
-			Results:  make([]*Var, 0, len(results)),
-			Variadic: variadic,
+			Name:       methodName,
+			Params:     make([]*Var, 0, len(params)),
+			Results:    make([]*Var, 0, len(results)),
+			Variadic:   variadic,
+			AllowError: iface.AllowError,
+			Timeout:    iface.Timeout,
+		}
+
+		doc := a.methodDoc(o.Pos())
+		method.Doc = doc.Text()
+		a.methodDirectives(method, qualName, doc)
+
+		if method.Skip {
+			// Skipped methods are never called over RPC, so their
+			// parameters and results need no analysis.
+			for _, param := range params {
+				method.Params = append(method.Params, &Var{Name: param.Name(), Typ: param.Type()})
+			}
+
+			for _, result := range results {
+				method.Results = append(method.Results, &Var{Name: result.Name(), Typ: result.Type()})
+			}
+
+			iface.Methods = append(iface.Methods, method)
+			continue
+		}
+
+		if method.Oneway && len(results) != 0 {
+			a.report(Error, o.Pos(), qualName, -1, -1,
+				"one-way method %s must not have results", qualName)
 		}
 
 		if variadic {
-			last := params[len(params)-1].Type().Underlying().(*types.Slice).Elem()
+			lastIdx := len(params) - 1
+			last := params[lastIdx].Type().Underlying().(*types.Slice).Elem()
 			if types.IsInterface(last) {
-				log.Fatalf("error: variadic interface arguments in %s.%s are unsupported", typeString, methodName)
+				a.report(Error, params[lastIdx].Pos(), qualName, lastIdx, -1,
+					"variadic interface arguments in %s are unsupported", qualName)
 			}
 		}
 
-		for _, param := range params {
+		for i, param := range params {
 			typ := param.Type()
 
 			v := &Var{
@@ -111,20 +255,28 @@ func (a *Analyzer) analyze(t types.Type) *Interface {
 
 			if typesext.IsPluggable(typ) {
 				v.IFace = a.analyze(typ)
+
+				if method.Idempotent {
+					a.report(Error, param.Pos(), qualName, i, -1,
+						"idempotent method %s cannot have interface parameters, as they cannot be brokered again on retry", qualName)
+				}
 			} else {
 				if typesext.IsEmptyInterface(typ) {
-					log.Printf("warning: empty interface parameter in %s.%s may not be compatible", typeString, methodName)
-				} else if a.allowError && typesext.IsError(typ) {
-					log.Printf("warning: error interface parameter in %s.%s may not be compatible", typeString, methodName)
+					a.report(Warning, param.Pos(), qualName, i, -1,
+						"empty interface parameter in %s may not be compatible", qualName)
+				} else if method.AllowError && typesext.IsError(typ) {
+					a.report(Warning, param.Pos(), qualName, i, -1,
+						"error interface parameter in %s may not be compatible", qualName)
 				} else if typesext.IsPointerLike(typ) {
-					log.Printf("warning: pointer-like parameter in %s.%s, writes made in a plugin will not propogate", typeString, methodName)
+					a.report(Warning, param.Pos(), qualName, i, -1,
+						"pointer-like parameter in %s, writes made in a plugin will not propogate", qualName)
 				}
 			}
 
 			method.Params = append(method.Params, v)
 		}
 
-		for _, result := range results {
+		for i, result := range results {
 			typ := result.Type()
 
 			v := &Var{
@@ -133,12 +285,15 @@ func (a *Analyzer) analyze(t types.Type) *Interface {
 			}
 
 			if typesext.IsPluggable(typ) {
-				log.Fatalf("error: non-empty or error interface return in %s.%s is unsupported", typeString, methodName)
+				a.report(Error, result.Pos(), qualName, -1, i,
+					"non-empty or error interface return in %s is unsupported", qualName)
 			} else {
 				if typesext.IsEmptyInterface(typ) {
-					log.Printf("warning: empty interface result in %s.%s may not be compatible", typeString, methodName)
-				} else if a.allowError && typesext.IsError(typ) {
-					log.Printf("warning: error interface result in %s.%s may not be compatible", typeString, methodName)
+					a.report(Warning, result.Pos(), qualName, -1, i,
+						"empty interface result in %s may not be compatible", qualName)
+				} else if method.AllowError && typesext.IsError(typ) {
+					a.report(Warning, result.Pos(), qualName, -1, i,
+						"error interface result in %s may not be compatible", qualName)
 				}
 			}
 
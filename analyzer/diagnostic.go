@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"errors"
+	"go/token"
+)
+
+// ErrUnsupported is returned by AnalyzeAll when any error diagnostics were
+// reported.
+var ErrUnsupported = errors.New("interfaces use unsupported features")
+
+// Severity is the severity of a Diagnostic.
+type Severity int
+
+const (
+	// Warning diagnostics describe code that may not work as expected.
+	Warning Severity = iota
+	// Error diagnostics describe code that cannot be generated.
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Diagnostic is a problem found while analyzing an interface.
+type Diagnostic struct {
+	Severity Severity
+	Pos      token.Position
+
+	// Method is the name of the method the diagnostic refers to, qualified
+	// by the interface type's string, such as
+	// "github.com/jakebailey/plugingen/example.Thinger.Identity", or empty.
+	Method string
+
+	// Param is the index of the parameter the diagnostic refers to, or -1.
+	Param int
+
+	// Result is the index of the result the diagnostic refers to, or -1.
+	Result int
+
+	Message string
+}
+
+// String formats the diagnostic as "file:line:col: severity: message".
+func (d Diagnostic) String() string {
+	return d.Pos.String() + ": " + d.Severity.String() + ": " + d.Message
+}
@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"time"
+
+	"github.com/jakebailey/plugingen/typesext"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const directivePrefix = "//plugingen:"
+
+// directive is a single "//plugingen:key=value" comment.
+type directive struct {
+	pos   token.Pos
+	key   string
+	value string
+}
+
+func parseDirectives(doc *ast.CommentGroup) []directive {
+	if doc == nil {
+		return nil
+	}
+
+	var ds []directive
+
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, directivePrefix) {
+			continue
+		}
+
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, directivePrefix))
+		d := directive{pos: c.Pos(), key: text}
+
+		if i := strings.IndexByte(text, '='); i != -1 {
+			d.key = text[:i]
+			d.value = text[i+1:]
+		}
+
+		ds = append(ds, d)
+	}
+
+	return ds
+}
+
+// pathTo returns the path of AST nodes enclosing pos, innermost first.
+func (a *Analyzer) pathTo(pos token.Pos) []ast.Node {
+	if !pos.IsValid() {
+		return nil
+	}
+
+	for _, f := range a.files {
+		if f.Pos() <= pos && pos < f.End() {
+			path, _ := astutil.PathEnclosingInterval(f, pos, pos)
+			return path
+		}
+	}
+
+	return nil
+}
+
+// typeDoc returns the doc comment of the type declared at pos.
+func (a *Analyzer) typeDoc(pos token.Pos) *ast.CommentGroup {
+	path := a.pathTo(pos)
+
+	for i, n := range path {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+
+		if spec.Doc != nil {
+			return spec.Doc
+		}
+
+		// Ungrouped declarations attach the comment to the GenDecl.
+		if i+1 < len(path) {
+			if decl, ok := path[i+1].(*ast.GenDecl); ok && !decl.Lparen.IsValid() {
+				return decl.Doc
+			}
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// methodDoc returns the doc comment of the interface method declared at pos.
+func (a *Analyzer) methodDoc(pos token.Pos) *ast.CommentGroup {
+	for _, n := range a.pathTo(pos) {
+		if field, ok := n.(*ast.Field); ok {
+			return field.Doc
+		}
+	}
+
+	return nil
+}
+
+// MaxOptional is the maximum number of optional interfaces an interface may
+// list, as a wrapper type is generated for each combination of them.
+const MaxOptional = 4
+
+func (a *Analyzer) interfaceDirectives(iface *Interface, named *types.Named, doc *ast.CommentGroup) {
+	for _, d := range parseDirectives(doc) {
+		switch d.key {
+		case "allowerror":
+			iface.AllowError = true
+
+		case "timeout":
+			if timeout, ok := a.parseTimeout(d, ""); ok {
+				iface.Timeout = timeout
+			}
+
+		case "name":
+			if !token.IsIdentifier(d.value) {
+				a.report(Error, d.pos, "", -1, -1, "invalid name directive %q, must be an identifier", d.value)
+				continue
+			}
+			iface.Name = d.value
+
+		case "optional":
+			a.parseOptional(iface, named, d)
+
+		default:
+			a.report(Warning, d.pos, "", -1, -1, "unknown interface directive %q", d.key)
+		}
+	}
+}
+
+func (a *Analyzer) methodDirectives(method *Method, qualName string, doc *ast.CommentGroup) {
+	for _, d := range parseDirectives(doc) {
+		switch d.key {
+		case "allowerror":
+			method.AllowError = true
+
+		case "skip":
+			method.Skip = true
+
+		case "oneway":
+			method.Oneway = true
+
+		case "retain":
+			method.Retain = true
+
+		case "idempotent":
+			method.Idempotent = true
+
+		case "timeout":
+			if timeout, ok := a.parseTimeout(d, qualName); ok {
+				method.Timeout = timeout
+			}
+
+		default:
+			a.report(Warning, d.pos, qualName, -1, -1, "unknown method directive %q", d.key)
+		}
+	}
+}
+
+// parseOptional resolves the comma-separated interface names of an optional
+// directive in the scope of the file declaring named, then analyzes them.
+func (a *Analyzer) parseOptional(iface *Interface, named *types.Named, d directive) {
+	for _, name := range strings.Split(d.value, ",") {
+		name = strings.TrimSpace(name)
+
+		tv, err := types.Eval(a.fset, named.Obj().Pkg(), named.Obj().Pos(), name)
+		if err != nil || !tv.IsType() {
+			a.report(Error, d.pos, "", -1, -1, "invalid optional directive: %q is not a type", name)
+			continue
+		}
+
+		if !typesext.IsPluggable(tv.Type) {
+			a.report(Error, d.pos, "", -1, -1, "invalid optional directive: %s is not a non-empty interface", name)
+			continue
+		}
+
+		if len(iface.Optional) == MaxOptional {
+			a.report(Error, d.pos, "", -1, -1, "too many optional interfaces for %s, at most %d are supported", named, MaxOptional)
+			return
+		}
+
+		iface.Optional = append(iface.Optional, a.analyze(tv.Type))
+	}
+}
+
+func (a *Analyzer) parseTimeout(d directive, qualName string) (time.Duration, bool) {
+	timeout, err := time.ParseDuration(d.value)
+	if err != nil || timeout <= 0 {
+		a.report(Error, d.pos, qualName, -1, -1, "invalid timeout directive %q, must be a positive duration", d.value)
+		return 0, false
+	}
+	return timeout, true
+}
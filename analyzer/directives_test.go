@@ -0,0 +1,273 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// analyzeSource analyzes the named interfaces declared in src, a file of
+// package p.
+func analyzeSource(t *testing.T, src string, names ...string) ([]*Interface, []Diagnostic, error) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("p", fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ts []types.Type
+	for _, name := range names {
+		ts = append(ts, pkg.Scope().Lookup(name).Type())
+	}
+
+	return NewAnalyzer(false, fset, []*ast.File{f}).AnalyzeAll(ts)
+}
+
+func TestInterfaceDirectives(t *testing.T) {
+	const src = `package p
+
+// Thinger does things.
+//
+//plugingen:name=Doer
+//plugingen:allowerror
+type Thinger interface {
+	Thing() error
+
+	// Other is documented.
+	//plugingen:allowerror
+	Other(err error)
+}
+
+type Plain interface {
+	Thing() error
+}
+`
+
+	ifaces, diags, err := analyzeSource(t, src, "Thinger", "Plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range diags {
+		if d.Severity == Error {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+
+	plain, thinger := ifaces[0], ifaces[1]
+
+	if thinger.Name != "Doer" || !thinger.AllowError {
+		t.Errorf("Thinger has Name %q, AllowError %v; want Doer, true", thinger.Name, thinger.AllowError)
+	}
+
+	if thinger.Doc != "Thinger does things.\n" {
+		t.Errorf("Thinger has Doc %q", thinger.Doc)
+	}
+
+	for _, m := range thinger.Methods {
+		if !m.AllowError {
+			t.Errorf("Thinger.%s has AllowError false; want true from the interface", m.Name)
+		}
+	}
+
+	if plain.Name != "" || plain.AllowError || plain.Methods[0].AllowError {
+		t.Errorf("Plain has directives applied: %+v", plain)
+	}
+}
+
+func TestDirectiveDiagnostics(t *testing.T) {
+	const src = `package p
+
+//plugingen:bogus
+//plugingen:name=not-an-identifier
+type Thinger interface {
+	//plugingen:whatever=1
+	Thing()
+
+	// Directives must start the comment line.
+	// plugingen:bogus
+	Other()
+}
+`
+
+	_, diags, err := analyzeSource(t, src, "Thinger")
+	if err != ErrUnsupported {
+		t.Errorf("AnalyzeAll() returned error %v; want %v", err, ErrUnsupported)
+	}
+
+	want := []struct {
+		severity Severity
+		line     int
+		method   string
+		message  string
+	}{
+		{Warning, 3, "", `unknown interface directive "bogus"`},
+		{Error, 4, "", `invalid name directive "not-an-identifier", must be an identifier`},
+		{Warning, 6, "p.Thinger.Thing", `unknown method directive "whatever"`},
+	}
+
+	if len(diags) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d: %v", len(diags), len(want), diags)
+	}
+
+	for i, w := range want {
+		d := diags[i]
+		if d.Severity != w.severity || d.Pos.Line != w.line || d.Method != w.method || d.Message != w.message {
+			t.Errorf("diagnostic %d = %v (method %q); want %v at line %d: %s (method %q)",
+				i, d, d.Method, w.severity, w.line, w.message, w.method)
+		}
+	}
+}
+
+func TestMethodDirectives(t *testing.T) {
+	const src = `package p
+
+type Thinger interface {
+	// Skipped methods are not analyzed, so may return interfaces.
+	//plugingen:skip
+	Lock() interface{ Unlock() }
+
+	//plugingen:oneway
+	Notify(event string)
+
+	//plugingen:timeout=250ms
+	Slow() error
+
+	//plugingen:retain
+	Watch(w interface{ Changed(key string) }) error
+
+	Plain()
+}
+`
+
+	ifaces, diags, err := analyzeSource(t, src, "Thinger")
+	if err != nil {
+		t.Fatalf("AnalyzeAll() = %v: %v", err, diags)
+	}
+
+	methods := map[string]*Method{}
+	for _, iface := range ifaces {
+		if iface.TopLevel {
+			for _, m := range iface.Methods {
+				methods[m.Name] = m
+			}
+		}
+	}
+
+	if m := methods["Lock"]; !m.Skip || m.Results[0].IFace != nil {
+		t.Errorf("Lock has Skip %v and an analyzed result; want skipped", m.Skip)
+	}
+
+	if m := methods["Notify"]; !m.Oneway {
+		t.Errorf("Notify has Oneway false; want true")
+	}
+
+	if m := methods["Slow"]; m.Timeout != 250*time.Millisecond {
+		t.Errorf("Slow has Timeout %v; want 250ms", m.Timeout)
+	}
+
+	if m := methods["Watch"]; !m.Retain || m.Params[0].IFace == nil {
+		t.Errorf("Watch has Retain %v; want true with a brokered parameter", m.Retain)
+	}
+
+	if m := methods["Plain"]; m.Skip || m.Oneway || m.Retain || m.Timeout != 0 {
+		t.Errorf("Plain has directives applied: %+v", m)
+	}
+}
+
+func TestMethodDirectiveErrors(t *testing.T) {
+	const src = `package p
+
+type Thinger interface {
+	//plugingen:oneway
+	Get() string
+
+	//plugingen:timeout=soon
+	Soon()
+
+	//plugingen:timeout=-1s
+	Negative()
+}
+`
+
+	_, diags, err := analyzeSource(t, src, "Thinger")
+	if err != ErrUnsupported {
+		t.Errorf("AnalyzeAll() returned error %v; want %v", err, ErrUnsupported)
+	}
+
+	want := []string{
+		`invalid timeout directive "-1s", must be a positive duration`,
+		`invalid timeout directive "soon", must be a positive duration`,
+		"one-way method p.Thinger.Get must not have results",
+	}
+
+	var got []string
+	for _, d := range diags {
+		if d.Severity != Error {
+			t.Errorf("diagnostic %v is not an error", d)
+		}
+		got = append(got, d.Message)
+	}
+	sort.Strings(got)
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got diagnostics:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestTimeoutDirectives(t *testing.T) {
+	const src = `package p
+
+//plugingen:timeout=5s
+type Fetcher interface {
+	Fetch(url string) ([]byte, error)
+
+	//plugingen:timeout=100ms
+	Ping()
+}
+
+//plugingen:timeout=never
+type Broken interface {
+	Ping()
+}
+`
+
+	ifaces, diags, err := analyzeSource(t, src, "Fetcher")
+	if err != nil {
+		t.Fatalf("AnalyzeAll() = %v: %v", err, diags)
+	}
+
+	fetcher := ifaces[0]
+	if fetcher.Timeout != 5*time.Second {
+		t.Errorf("Fetcher has Timeout %v; want 5s", fetcher.Timeout)
+	}
+
+	for _, m := range fetcher.Methods {
+		want := 5 * time.Second
+		if m.Name == "Ping" {
+			want = 100 * time.Millisecond
+		}
+		if m.Timeout != want {
+			t.Errorf("Fetcher.%s has Timeout %v; want %v", m.Name, m.Timeout, want)
+		}
+	}
+
+	_, diags, err = analyzeSource(t, src, "Broken")
+	if err != ErrUnsupported || len(diags) != 1 || diags[0].Message != `invalid timeout directive "never", must be a positive duration` {
+		t.Errorf("AnalyzeAll() of Broken = %v: %v", err, diags)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"math/rand"
+	goruntime "runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			want: "",
+		},
+		{
+			name: "empty old",
+			a:    "",
+			b:    "a\nb\n",
+			want: "--- a.go\n+++ b.go\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "empty new",
+			a:    "a\nb\n",
+			b:    "",
+			want: "--- a.go\n+++ b.go\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "trailing newline",
+			a:    "a\nb\n",
+			b:    "a\nb",
+			want: "--- a.go\n+++ b.go\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n",
+		},
+		{
+			name: "context",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:    "1\n2\n3\n4\nx\n6\n7\n8\n",
+			want: "--- a.go\n+++ b.go\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+x\n 6\n 7\n 8\n",
+		},
+		{
+			name: "merged hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:    "x\n2\n3\n4\n5\n6\n7\ny\n",
+			want: "--- a.go\n+++ b.go\n@@ -1,8 +1,8 @@\n-1\n+x\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+y\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:    "x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n",
+			want: "--- a.go\n+++ b.go\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+y\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := unifiedDiff("a.go", "b.go", []byte(test.a), []byte(test.b))
+			if got != test.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+// TestDiffLines checks that the edit script turns a into b using the fewest
+// edits, comparing against the length of a longest common subsequence.
+func TestDiffLines(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	lines := func(n int) []string {
+		s := make([]string, n)
+		for i := range s {
+			s[i] = string(rune('a' + r.Intn(3)))
+		}
+		return s
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := lines(r.Intn(12)), lines(r.Intn(12))
+		ops := diffLines(a, b)
+
+		var gotA, gotB []string
+		edits := 0
+		for _, op := range ops {
+			if op.kind != '+' {
+				gotA = append(gotA, op.line)
+			}
+			if op.kind != '-' {
+				gotB = append(gotB, op.line)
+			}
+			if op.kind != ' ' {
+				edits++
+			}
+		}
+
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("diffLines(%q, %q) = %v; does not turn one into the other", a, b, ops)
+		}
+		if want := len(a) + len(b) - 2*lcsLen(a, b); edits != want {
+			t.Fatalf("diffLines(%q, %q) made %d edits; want %d", a, b, edits, want)
+		}
+	}
+}
+
+func lcsLen(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	return lcs[0][0]
+}
+
+// TestDiffLinesLarge checks that large files with changes throughout are
+// compared without allocating memory quadratic in their length, which would
+// be gigabytes here.
+func TestDiffLinesLarge(t *testing.T) {
+	a, b := make([]string, 20000), make([]string, 20000)
+	for i := range a {
+		a[i] = strconv.Itoa(i)
+		b[i] = a[i]
+		if i%10 == 0 {
+			b[i] = "x" + a[i]
+		}
+	}
+
+	var stats goruntime.MemStats
+	goruntime.ReadMemStats(&stats)
+	before := stats.TotalAlloc
+	ops := diffLines(a, b)
+	goruntime.ReadMemStats(&stats)
+	if used := stats.TotalAlloc - before; used > 64<<20 {
+		t.Errorf("diffLines allocated %d bytes", used)
+	}
+	if len(ops) != 22000 {
+		t.Errorf("diffLines returned %d ops; want 22000", len(ops))
+	}
+}
@@ -0,0 +1,327 @@
+// Command plugingen generates code to use arbitrary interfaces with
+// hashicorp's go-plugin.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jakebailey/plugingen"
+	"github.com/jakebailey/plugingen/generator"
+)
+
+var (
+	typeNames    = flag.String("type", "", "comma-separated list of type names; must be set")
+	output       = flag.String("output", "", "output file name (or - for stdout); default <srcdir>/plugingen.go")
+	buildTags    = flag.String("tags", "", "comma-separated list of build tags to apply")
+	allowError   = flag.Bool("allowerror", false, "don't wrap errors with plugin.BasicError")
+	subPkg       = flag.String("subpkg", "", "subpackage name for generated code; if specified, output will be written to <srcdir>/<subpkg>/<output>")
+	rpcPanic     = flag.Bool("panicrpc", false, "panic on RPC call errors")
+	werror       = flag.Bool("Werror", false, "treat warnings as errors")
+	timeout      = flag.Duration("timeout", 0, "default timeout for RPC calls; 0 means no timeout")
+	timeoutClose = flag.Bool("timeoutclose", false, "close the plugin connection after an RPC call times out")
+	supervisor   = flag.Bool("supervisor", false, "generate supervisor wrappers which restart the plugin process")
+	batch        = flag.Bool("batch", false, "generate batch clients which send many calls in a single RPC")
+	backend      = flag.String("backend", "netrpc", "generated code: netrpc for go-plugin, or jsonrpc for JSON-RPC 2.0 plugins in other languages")
+	embedded     = flag.Bool("embedded", false, "share generated code for embedded interfaces between the interfaces embedding them")
+	docs         = flag.Bool("docs", false, "also generate PLUGIN_API.md documenting the interfaces")
+	mocks        = flag.Bool("mocks", false, "also generate recording mocks of each interface, in a _mock.go file")
+	conformance  = flag.Bool("conformance", false, "also generate a conformance test harness, in a _test.go file")
+	unexported   = flag.Bool("unexported", false, "keep parameter and result types unexported, dispatching calls through a single Call method")
+	split        = flag.Bool("split", false, "write the code for each interface to its own file, such as <type>_plugingen.go")
+	naming       = namingFlag{}
+	check        = flag.Bool("check", false, "don't write files; exit non-zero with a diff if they are not up to date")
+	codec        = flag.String("codec", "gob", "codec for RPC connections: gob, json, or a name registered with runtime.RegisterCodec")
+)
+
+// Usage is a replacement usage function for the flags package.
+func Usage() {
+	fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\tplugingen [flags] -type T [directory]\n")
+	fmt.Fprintf(os.Stderr, "\tplugingen [flags] -type T files... # Must be a single package\n")
+	fmt.Fprintf(os.Stderr, "\tplugingen -check [flags] -type T [directory] # Verify generated files are up to date\n")
+	fmt.Fprintf(os.Stderr, "\tplugingen schema [flags] -type T [directory] # Print the wire protocol as JSON\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("plugingen: ")
+	flag.Usage = Usage
+	flag.Var(naming, "naming", "override the text/template naming a category of generated identifiers, as category=template; may be repeated (categories: "+strings.Join(generator.NamingCategories(), ", ")+")")
+
+	args := os.Args[1:]
+	schemaCmd := len(args) != 0 && args[0] == "schema"
+	if schemaCmd {
+		args = args[1:]
+	}
+	flag.CommandLine.Parse(args)
+
+	if len(*typeNames) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	config := plugingen.Config{
+		Types:        splitTypes(*typeNames),
+		Args:         flag.Args(),
+		BuildTags:    strings.Split(*buildTags, ","),
+		Output:       *output,
+		SubPkg:       *subPkg,
+		AllowError:   *allowError,
+		RPCPanic:     *rpcPanic,
+		Timeout:      *timeout,
+		TimeoutClose: *timeoutClose,
+		Supervisor:   *supervisor,
+		Batch:        *batch,
+		Codec:        *codec,
+		Backend:      *backend,
+		Embedded:     *embedded,
+		Docs:         *docs,
+		Mocks:        *mocks,
+		Conformance:  *conformance,
+		Unexported:   *unexported,
+		Split:        *split,
+		Naming:       naming,
+		Command:      "plugingen " + strings.Join(commandArgs(os.Args[1:]), " "),
+		Logger:       log.Default(),
+	}
+
+	if schemaCmd {
+		if err := runSchema(context.Background(), config, *werror); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *check {
+		if err := runCheck(context.Background(), config, *werror, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := run(context.Background(), config, *werror); err != nil {
+		log.Fatal(err)
+	}
+}
+
+var (
+	errWarnings    = errors.New("warnings found and -Werror specified")
+	errOutOfDate   = errors.New("generated files are out of date; run go generate")
+	errCheckStdout = errors.New("-check cannot be used with -output -")
+	errMultiStdout = errors.New("-output - cannot be used with -split, -docs, -mocks, -conformance or -backend=jsonrpc, which write more than one file")
+)
+
+// namingFlag collects category=template pairs passed to -naming.
+type namingFlag map[string]string
+
+func (f namingFlag) String() string {
+	var pairs []string
+	for category, text := range f {
+		pairs = append(pairs, category+"="+text)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+func (f namingFlag) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("expected category=template, got %q", value)
+	}
+	f[value[:i]] = value[i+1:]
+	return nil
+}
+
+// splitTypes splits a comma-separated list of type names, ignoring commas
+// within the type arguments of generic types, as in Store[string,User].
+func splitTypes(list string) []string {
+	var names []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				names = append(names, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(names, list[start:])
+}
+
+// commandArgs returns args without -check, so that checking records the
+// same command line in generated files as generating does.
+func commandArgs(args []string) []string {
+	var filtered []string
+	for _, arg := range args {
+		switch strings.TrimLeft(arg, "-") {
+		case "check", "check=true", "check=false":
+			if strings.HasPrefix(arg, "-") {
+				continue
+			}
+		}
+		filtered = append(filtered, arg)
+	}
+	return filtered
+}
+
+func run(ctx context.Context, config plugingen.Config, werror bool) error {
+	toStdout := config.Output == "-"
+	if toStdout {
+		if config.Split || config.Docs || config.Mocks || config.Conformance || config.Backend == "jsonrpc" {
+			return errMultiStdout
+		}
+		config.Output = ""
+	}
+
+	files, diags, err := plugingen.Generate(ctx, config)
+
+	for _, d := range diags {
+		fmt.Fprintln(os.Stderr, d)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if werror && len(diags) != 0 {
+		return errWarnings
+	}
+
+	if toStdout {
+		// Stale split files are left alone, as nothing is written to disk.
+		for name, contents := range files {
+			if contents == nil {
+				delete(files, name)
+			}
+		}
+
+		if len(files) != 1 {
+			return errMultiStdout
+		}
+		for _, contents := range files {
+			_, err := os.Stdout.Write(contents)
+			return err
+		}
+	}
+
+	for name, contents := range files {
+		if contents == nil {
+			// A stale file from an earlier run with -split.
+			if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(name, contents, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runCheck generates files as run does, but compares them to the files on
+// disk instead of writing them, printing a unified diff of each difference
+// to w.
+func runCheck(ctx context.Context, config plugingen.Config, werror bool, w io.Writer) error {
+	if config.Output == "-" {
+		return errCheckStdout
+	}
+
+	files, diags, err := plugingen.Generate(ctx, config)
+
+	for _, d := range diags {
+		fmt.Fprintln(os.Stderr, d)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if werror && len(diags) != 0 {
+		return errWarnings
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	upToDate := true
+	for _, name := range names {
+		old, err := ioutil.ReadFile(name)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		newName := name + " (generated)"
+		if files[name] == nil {
+			newName = name + " (removed)"
+		}
+
+		if diff := unifiedDiff(name, newName, old, files[name]); diff != "" {
+			upToDate = false
+			if _, err := io.WriteString(w, diff); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !upToDate {
+		return errOutOfDate
+	}
+	return nil
+}
+
+func runSchema(ctx context.Context, config plugingen.Config, werror bool) error {
+	sch, diags, err := plugingen.Schema(ctx, config)
+
+	for _, d := range diags {
+		fmt.Fprintln(os.Stderr, d)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if werror && len(diags) != 0 {
+		return errWarnings
+	}
+
+	b, err := json.MarshalIndent(sch, "", "\t")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	if config.Output == "" || config.Output == "-" {
+		_, err := os.Stdout.Write(b)
+		return err
+	}
+
+	return ioutil.WriteFile(config.Output, b, 0644)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jakebailey/plugingen"
+)
+
+// checkDir copies testdata/check into a new directory within testdata, so
+// that it can be loaded as part of the module.
+func checkDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("testdata", "check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "check", "input.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "input.go"), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestRunCheck(t *testing.T) {
+	dir := checkDir(t)
+	config := plugingen.Config{
+		Types:  []string{"Thinger"},
+		Args:   []string{"./" + filepath.ToSlash(dir)},
+		Split:  true,
+		SubPkg: "plug",
+
+		// Stale files are found whatever command generated them.
+		Command: "go run ./cmd/plugingen -type=Thinger -split",
+	}
+	ctx := context.Background()
+	output := filepath.Join(dir, "plug", "thinger_plugingen.go")
+
+	var buf bytes.Buffer
+	if err := runCheck(ctx, config, false, &buf); err != errOutOfDate {
+		t.Fatalf("runCheck() before generating = %v; want %v", err, errOutOfDate)
+	}
+	if !strings.Contains(buf.String(), "+++ "+output+" (generated)") {
+		t.Errorf("runCheck() before generating printed:\n%s", buf.String())
+	}
+
+	if err := run(ctx, config, false); err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+	if err := runCheck(ctx, config, false, &buf); err != nil {
+		t.Fatalf("runCheck() after generating = %v\n%s", err, buf.String())
+	}
+
+	stale := filepath.Join(dir, "plug", "other_plugingen.go")
+	b, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(stale, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+	if err := runCheck(ctx, config, false, &buf); err != errOutOfDate {
+		t.Fatalf("runCheck() with a stale file = %v; want %v", err, errOutOfDate)
+	}
+	if !strings.Contains(buf.String(), "+++ "+stale+" (removed)") {
+		t.Errorf("runCheck() with a stale file printed:\n%s", buf.String())
+	}
+
+	if err := run(ctx, config, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("run() did not remove %s: %v", stale, err)
+	}
+}
+
+func TestRunStdoutWithStaleFiles(t *testing.T) {
+	dir := checkDir(t)
+	config := plugingen.Config{
+		Types:  []string{"Thinger"},
+		Args:   []string{"./" + filepath.ToSlash(dir)},
+		Split:  true,
+		SubPkg: "plug",
+	}
+	ctx := context.Background()
+
+	if err := run(ctx, config, false); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ioutil.TempFile("", "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	stdout := os.Stdout
+	os.Stdout = f
+	defer func() { os.Stdout = stdout }()
+
+	config.Split = false
+	config.Output = "-"
+	if err := run(ctx, config, false); err != nil {
+		t.Fatalf("run() to stdout with stale split files = %v", err)
+	}
+
+	split := filepath.Join(dir, "plug", "thinger_plugingen.go")
+	if _, err := os.Stat(split); err != nil {
+		t.Errorf("run() to stdout removed %s: %v", split, err)
+	}
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte("ThingerRPCClient")) {
+		t.Errorf("run() wrote to stdout:\n%s", b)
+	}
+}
@@ -0,0 +1,5 @@
+package check
+
+type Thinger interface {
+	Thing(name string) (int, error)
+}
@@ -5,12 +5,15 @@ import (
 	"io"
 )
 
-//go:generate go run .. -type=Thinger -subpkg=exampleplug -panicrpc .
+//go:generate go run ../cmd/plugingen -type=Thinger -subpkg=exampleplug -panicrpc -supervisor -batch -docs -mocks -conformance .
 
 type Thinger interface {
 	fmt.Stringer
 	DoNothing()
+
+	//plugingen:idempotent
 	Sum(...int) int
+
 	Copy(io.Writer, io.Reader) (int64, error)
 	ErrorToError(error) error
 	Identity(interface{}) interface{}
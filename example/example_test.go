@@ -13,6 +13,7 @@ import (
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/jakebailey/plugingen/example"
 	"github.com/jakebailey/plugingen/example/exampleplug"
+	"github.com/jakebailey/plugingen/runtime"
 )
 
 func TestString(t *testing.T) {
@@ -123,6 +124,87 @@ func TestReplace(t *testing.T) {
 	}
 }
 
+func TestMock(t *testing.T) {
+	mock := &exampleplug.ThingerMock{
+		SumFunc: func(p0 ...int) int { return len(p0) },
+	}
+
+	var thinger example.Thinger = mock
+	thinger.DoNothing()
+
+	if got := thinger.Sum(5, 6, 7); got != 3 {
+		t.Errorf("thinger.Sum(5, 6, 7) = %v; want 3", got)
+	}
+
+	if got := thinger.String(); got != "" {
+		t.Errorf("thinger.String() = %q; want zero value", got)
+	}
+
+	calls := mock.Calls.All()
+	if len(calls) != 3 {
+		t.Fatalf("recorded %d calls; want 3", len(calls))
+	}
+
+	want := [][]interface{}{{[]int{5, 6, 7}}}
+	if got := mock.Calls.For("Sum"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sum calls = %v; want %v", got, want)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	thinger, cleanup := makeThinger(t)
+	defer cleanup()
+
+	batch := thinger.(*exampleplug.ThingerRPCClient).Batch()
+
+	sum := batch.Sum(1, 2, 3, 4)
+	batch.DoNothing()
+	str := batch.String()
+
+	if got := batch.Len(); got != 3 {
+		t.Errorf("batch.Len() = %v; want 3", got)
+	}
+
+	if err := batch.Flush(); err != nil {
+		t.Fatalf("batch.Flush() returned error %v; want nil", err)
+	}
+
+	if got, want := sum.R0, 1+2+3+4; got != want {
+		t.Errorf("batch.Sum(1, 2, 3, 4) = %v; want %v", got, want)
+	}
+
+	if got, want := str.R0, "fakeThinger"; got != want {
+		t.Errorf("batch.String() = %v; want %v", got, want)
+	}
+
+	if got := batch.Len(); got != 0 {
+		t.Errorf("batch.Len() after Flush = %v; want 0", got)
+	}
+}
+
+func TestSupervisorRestart(t *testing.T) {
+	thinger, err := exampleplug.NewThingerSupervisor("thinger", func() *plugin.ClientConfig {
+		return &plugin.ClientConfig{
+			Cmd:             helperProcess(),
+			HandshakeConfig: exampleplug.PluginHandshake,
+			Plugins:         pluginSet,
+			Logger:          hclog.NewNullLogger(),
+		}
+	}, runtime.RetryPolicy{Attempts: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer thinger.Supervisor.Kill()
+
+	thinger.Supervisor.Kill()
+
+	got := thinger.Sum(1, 2)
+	want := 1 + 2
+	if got != want {
+		t.Errorf("thinger.Sum(1, 2) after restart = %v; want %v", got, want)
+	}
+}
+
 func BenchmarkSum(b *testing.B) {
 	thinger, cleanup := makeThingerExternal(b)
 	defer cleanup()
@@ -133,6 +215,28 @@ func BenchmarkSum(b *testing.B) {
 	}
 }
 
+func BenchmarkSumBatch(b *testing.B) {
+	thinger, cleanup := makeThingerExternal(b)
+	defer cleanup()
+
+	batch := thinger.(*exampleplug.ThingerRPCClient).Batch()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch.Sum(1, 2, 3, 4)
+
+		if batch.Len() == 100 {
+			if err := batch.Flush(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	if err := batch.Flush(); err != nil {
+		b.Fatal(err)
+	}
+}
+
 func BenchmarkSumLocal(b *testing.B) {
 	thinger := fakeThinger{}
 
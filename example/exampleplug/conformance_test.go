@@ -0,0 +1,41 @@
+package exampleplug
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jakebailey/plugingen/example"
+)
+
+type upper struct{}
+
+func (upper) Replace(s string) string {
+	return strings.ToUpper(s)
+}
+
+func TestConformThinger(t *testing.T) {
+	impl := &ThingerMock{
+		StringFunc: func() string { return "mock" },
+		SumFunc: func(p0 ...int) int {
+			sum := 0
+			for _, v := range p0 {
+				sum += v
+			}
+			return sum
+		},
+		ErrorToErrorFunc: func(p0 error) error { return p0 },
+		IdentityFunc:     func(p0 interface{}) interface{} { return p0 },
+		ReplaceFunc: func(p0 string, p1 interface{ Replace(string) string }) string {
+			return p1.Replace(p0)
+		},
+	}
+
+	ConformThinger(t, impl,
+		func(x example.Thinger) []interface{} { return []interface{}{x.Sum(1, 2, 3)} },
+		func(x example.Thinger) []interface{} { return []interface{}{x.Sum()} },
+		func(x example.Thinger) []interface{} { return []interface{}{x.ErrorToError(errors.New("oops"))} },
+		func(x example.Thinger) []interface{} { return []interface{}{x.Identity("value")} },
+		func(x example.Thinger) []interface{} { return []interface{}{x.Replace("abc", upper{})} },
+	)
+}
@@ -1,13 +1,14 @@
-// Code generated by "plugingen -type=Thinger -subpkg=exampleplug -panicrpc ."; DO NOT EDIT.
+// Code generated by "plugingen -type=Thinger -subpkg=exampleplug -panicrpc -supervisor -batch -docs -mocks -conformance ."; DO NOT EDIT.
 
 package exampleplug
 
 import (
 	"encoding/gob"
+	"fmt"
 	goplugin "github.com/hashicorp/go-plugin"
 	example "github.com/jakebailey/plugingen/example"
+	runtime "github.com/jakebailey/plugingen/runtime"
 	"io"
-	"log"
 	"net/rpc"
 )
 
@@ -34,19 +35,26 @@ func (p *ThingerPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{
 
 // ThingerRPCClient implements Thinger via net/rpc.
 type ThingerRPCClient struct {
-	broker *goplugin.MuxBroker
-	client *rpc.Client
+	client *runtime.Client
 }
 
 func NewThingerRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *ThingerRPCClient {
-	return &ThingerRPCClient{
-		broker: b,
-		client: c,
-	}
+	return &ThingerRPCClient{client: runtime.NewClient("Thinger", b, c, runtime.FatalError)}
 }
 
 var _ example.Thinger = (*ThingerRPCClient)(nil)
 
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *ThingerRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *ThingerRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
 // ThingerRPCServer implements the net/rpc server for Thinger.
 type ThingerRPCServer struct {
 	broker *goplugin.MuxBroker
@@ -76,67 +84,52 @@ type Z_Thinger_CopyResults struct {
 
 // Copy implements Copy for the Thinger interface.
 func (c *ThingerRPCClient) Copy(p0 io.Writer, p1 io.Reader) (int64, error) {
-	p0id := c.broker.NextId()
-	go c.broker.AcceptAndServe(p0id, NewWriterRPCServer(c.broker, p0))
-
-	p1id := c.broker.NextId()
-	go c.broker.AcceptAndServe(p1id, NewReaderRPCServer(c.broker, p1))
-
 	params := &Z_Thinger_CopyParams{
-		P0ID: p0id,
-		P1ID: p1id,
+		P0ID: c.client.Serve(NewWriterRPCServer(c.client.Broker(), p0)),
+		P1ID: c.client.Serve(NewReaderRPCServer(c.client.Broker(), p1)),
 	}
 	results := &Z_Thinger_CopyResults{}
 
-	if err := c.client.Call("Plugin.Copy", params, results); err != nil {
-		log.Fatalln("RPC call to Thinger.Copy failed:", err.Error())
-	}
+	c.client.CallWith("Copy", params, results, runtime.CallOptions{Brokered: true})
 
 	return results.R0, results.R1
 }
 
 // Copy implements the server side of net/rpc calls to Copy.
-func (s *ThingerRPCServer) Copy(params *Z_Thinger_CopyParams, results *Z_Thinger_CopyResults) error {
-	p0conn, err := s.broker.Dial(params.P0ID)
+func (s *ThingerRPCServer) Copy(params *Z_Thinger_CopyParams, results *Z_Thinger_CopyResults) (err error) {
+	defer runtime.Recover("Thinger.Copy", &err)
+
+	p0rpc, err := runtime.Dial(s.broker, params.P0ID)
 	if err != nil {
 		return err
 	}
-	p0RPCClient := rpc.NewClient(p0conn)
-	defer p0RPCClient.Close()
-	p0client := NewWriterRPCClient(s.broker, p0RPCClient)
+	defer p0rpc.Close()
+	p0client := NewWriterRPCClient(s.broker, p0rpc)
 
-	p1conn, err := s.broker.Dial(params.P1ID)
+	p1rpc, err := runtime.Dial(s.broker, params.P1ID)
 	if err != nil {
 		return err
 	}
-	p1RPCClient := rpc.NewClient(p1conn)
-	defer p1RPCClient.Close()
-	p1client := NewReaderRPCClient(s.broker, p1RPCClient)
+	defer p1rpc.Close()
+	p1client := NewReaderRPCClient(s.broker, p1rpc)
 
 	r0, r1 := s.impl.Copy(p0client, p1client)
 
 	results.R0 = r0
-	if r1 == nil {
-		results.R1 = nil
-	} else {
-		results.R1 = goplugin.NewBasicError(r1)
-	}
+	results.R1 = runtime.WrapError(r1)
 
 	return nil
 }
 
 // DoNothing implements DoNothing for the Thinger interface.
 func (c *ThingerRPCClient) DoNothing() {
-	params := new(interface{})
-	results := new(interface{})
-
-	if err := c.client.Call("Plugin.DoNothing", params, results); err != nil {
-		log.Fatalln("RPC call to Thinger.DoNothing failed:", err.Error())
-	}
+	c.client.Call("DoNothing", nil, nil)
 }
 
 // DoNothing implements the server side of net/rpc calls to DoNothing.
-func (s *ThingerRPCServer) DoNothing(_ interface{}, _ *interface{}) error {
+func (s *ThingerRPCServer) DoNothing(_ interface{}, _ *interface{}) (err error) {
+	defer runtime.Recover("Thinger.DoNothing", &err)
+
 	s.impl.DoNothing()
 
 	return nil
@@ -156,25 +149,21 @@ type Z_Thinger_ErrorToErrorResults struct {
 
 // ErrorToError implements ErrorToError for the Thinger interface.
 func (c *ThingerRPCClient) ErrorToError(p0 error) error {
-	params := &Z_Thinger_ErrorToErrorParams{P0: goplugin.NewBasicError(p0)}
+	params := &Z_Thinger_ErrorToErrorParams{P0: runtime.WrapError(p0)}
 	results := &Z_Thinger_ErrorToErrorResults{}
 
-	if err := c.client.Call("Plugin.ErrorToError", params, results); err != nil {
-		log.Fatalln("RPC call to Thinger.ErrorToError failed:", err.Error())
-	}
+	c.client.Call("ErrorToError", params, results)
 
 	return results.R0
 }
 
 // ErrorToError implements the server side of net/rpc calls to ErrorToError.
-func (s *ThingerRPCServer) ErrorToError(params *Z_Thinger_ErrorToErrorParams, results *Z_Thinger_ErrorToErrorResults) error {
+func (s *ThingerRPCServer) ErrorToError(params *Z_Thinger_ErrorToErrorParams, results *Z_Thinger_ErrorToErrorResults) (err error) {
+	defer runtime.Recover("Thinger.ErrorToError", &err)
+
 	r0 := s.impl.ErrorToError(params.P0)
 
-	if r0 == nil {
-		results.R0 = nil
-	} else {
-		results.R0 = goplugin.NewBasicError(r0)
-	}
+	results.R0 = runtime.WrapError(r0)
 
 	return nil
 }
@@ -196,15 +185,15 @@ func (c *ThingerRPCClient) Identity(p0 interface{}) interface{} {
 	params := &Z_Thinger_IdentityParams{P0: p0}
 	results := &Z_Thinger_IdentityResults{}
 
-	if err := c.client.Call("Plugin.Identity", params, results); err != nil {
-		log.Fatalln("RPC call to Thinger.Identity failed:", err.Error())
-	}
+	c.client.Call("Identity", params, results)
 
 	return results.R0
 }
 
 // Identity implements the server side of net/rpc calls to Identity.
-func (s *ThingerRPCServer) Identity(params *Z_Thinger_IdentityParams, results *Z_Thinger_IdentityResults) error {
+func (s *ThingerRPCServer) Identity(params *Z_Thinger_IdentityParams, results *Z_Thinger_IdentityResults) (err error) {
+	defer runtime.Recover("Thinger.Identity", &err)
+
 	r0 := s.impl.Identity(params.P0)
 
 	results.R0 = r0
@@ -229,31 +218,27 @@ type Z_Thinger_ReplaceResults struct {
 func (c *ThingerRPCClient) Replace(p0 string, p1 interface {
 	Replace(string) string
 }) string {
-	p1id := c.broker.NextId()
-	go c.broker.AcceptAndServe(p1id, NewZ_Interface0RPCServer(c.broker, p1))
-
 	params := &Z_Thinger_ReplaceParams{
 		P0:   p0,
-		P1ID: p1id,
+		P1ID: c.client.Serve(NewThingerReplaceP1RPCServer(c.client.Broker(), p1)),
 	}
 	results := &Z_Thinger_ReplaceResults{}
 
-	if err := c.client.Call("Plugin.Replace", params, results); err != nil {
-		log.Fatalln("RPC call to Thinger.Replace failed:", err.Error())
-	}
+	c.client.CallWith("Replace", params, results, runtime.CallOptions{Brokered: true})
 
 	return results.R0
 }
 
 // Replace implements the server side of net/rpc calls to Replace.
-func (s *ThingerRPCServer) Replace(params *Z_Thinger_ReplaceParams, results *Z_Thinger_ReplaceResults) error {
-	p1conn, err := s.broker.Dial(params.P1ID)
+func (s *ThingerRPCServer) Replace(params *Z_Thinger_ReplaceParams, results *Z_Thinger_ReplaceResults) (err error) {
+	defer runtime.Recover("Thinger.Replace", &err)
+
+	p1rpc, err := runtime.Dial(s.broker, params.P1ID)
 	if err != nil {
 		return err
 	}
-	p1RPCClient := rpc.NewClient(p1conn)
-	defer p1RPCClient.Close()
-	p1client := NewZ_Interface0RPCClient(s.broker, p1RPCClient)
+	defer p1rpc.Close()
+	p1client := NewThingerReplaceP1RPCClient(s.broker, p1rpc)
 
 	r0 := s.impl.Replace(params.P0, p1client)
 
@@ -270,18 +255,17 @@ type Z_Thinger_StringResults struct {
 
 // String implements String for the Thinger interface.
 func (c *ThingerRPCClient) String() string {
-	params := new(interface{})
 	results := &Z_Thinger_StringResults{}
 
-	if err := c.client.Call("Plugin.String", params, results); err != nil {
-		log.Fatalln("RPC call to Thinger.String failed:", err.Error())
-	}
+	c.client.Call("String", nil, results)
 
 	return results.R0
 }
 
 // String implements the server side of net/rpc calls to String.
-func (s *ThingerRPCServer) String(_ interface{}, results *Z_Thinger_StringResults) error {
+func (s *ThingerRPCServer) String(_ interface{}, results *Z_Thinger_StringResults) (err error) {
+	defer runtime.Recover("Thinger.String", &err)
+
 	r0 := s.impl.String()
 
 	results.R0 = r0
@@ -306,113 +290,304 @@ func (c *ThingerRPCClient) Sum(p0 ...int) int {
 	params := &Z_Thinger_SumParams{P0: p0}
 	results := &Z_Thinger_SumResults{}
 
-	if err := c.client.Call("Plugin.Sum", params, results); err != nil {
-		log.Fatalln("RPC call to Thinger.Sum failed:", err.Error())
-	}
+	c.client.CallWith("Sum", params, results, runtime.CallOptions{Idempotent: true})
 
 	return results.R0
 }
 
 // Sum implements the server side of net/rpc calls to Sum.
-func (s *ThingerRPCServer) Sum(params *Z_Thinger_SumParams, results *Z_Thinger_SumResults) error {
+func (s *ThingerRPCServer) Sum(params *Z_Thinger_SumParams, results *Z_Thinger_SumResults) (err error) {
+	defer runtime.Recover("Thinger.Sum", &err)
+
 	r0 := s.impl.Sum(params.P0...)
 
 	results.R0 = r0
 
 	return nil
 }
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Thinger_ErrorToErrorParams", &Z_Thinger_ErrorToErrorParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Thinger_ErrorToErrorResults", &Z_Thinger_ErrorToErrorResults{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Thinger_IdentityParams", &Z_Thinger_IdentityParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Thinger_IdentityResults", &Z_Thinger_IdentityResults{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Thinger_StringResults", &Z_Thinger_StringResults{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Thinger_SumParams", &Z_Thinger_SumParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Thinger_SumResults", &Z_Thinger_SumResults{})
+}
+
+// ThingerBatch queues calls to Thinger, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type ThingerBatch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new ThingerBatch which sends its calls through c.
+func (c *ThingerRPCClient) Batch() *ThingerBatch {
+	return &ThingerBatch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *ThingerBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *ThingerBatch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// DoNothing queues a call to DoNothing.
+func (b *ThingerBatch) DoNothing() {
+	b.batch.Add("DoNothing", nil, nil)
+}
+
+// ErrorToError queues a call to ErrorToError.
+// The returned results are filled in by Flush.
+func (b *ThingerBatch) ErrorToError(p0 error) *Z_Thinger_ErrorToErrorResults {
+	params := &Z_Thinger_ErrorToErrorParams{P0: runtime.WrapError(p0)}
+	results := &Z_Thinger_ErrorToErrorResults{}
+	b.batch.Add("ErrorToError", params, results)
+	return results
+}
+
+// Identity queues a call to Identity.
+// The returned results are filled in by Flush.
+func (b *ThingerBatch) Identity(p0 interface{}) *Z_Thinger_IdentityResults {
+	params := &Z_Thinger_IdentityParams{P0: p0}
+	results := &Z_Thinger_IdentityResults{}
+	b.batch.Add("Identity", params, results)
+	return results
+}
+
+// String queues a call to String.
+// The returned results are filled in by Flush.
+func (b *ThingerBatch) String() *Z_Thinger_StringResults {
+	results := &Z_Thinger_StringResults{}
+	b.batch.Add("String", nil, results)
+	return results
+}
+
+// Sum queues a call to Sum.
+// The returned results are filled in by Flush.
+func (b *ThingerBatch) Sum(p0 ...int) *Z_Thinger_SumResults {
+	params := &Z_Thinger_SumParams{P0: p0}
+	results := &Z_Thinger_SumResults{}
+	b.batch.Add("Sum", params, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *ThingerRPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "DoNothing":
+			return nil, s.DoNothing(nil, nil)
+		case "ErrorToError":
+			results := &Z_Thinger_ErrorToErrorResults{}
+			return results, s.ErrorToError(call.Params.(*Z_Thinger_ErrorToErrorParams), results)
+		case "Identity":
+			results := &Z_Thinger_IdentityResults{}
+			return results, s.Identity(call.Params.(*Z_Thinger_IdentityParams), results)
+		case "String":
+			results := &Z_Thinger_StringResults{}
+			return results, s.String(nil, results)
+		case "Sum":
+			results := &Z_Thinger_SumResults{}
+			return results, s.Sum(call.Params.(*Z_Thinger_SumParams), results)
+		}
+		return nil, fmt.Errorf("Thinger.%s cannot be batched", call.Method)
+	})
+}
+
+// ThingerSupervisor implements Thinger using a plugin process managed by a runtime.Supervisor,
+// which is restarted if the connection to it is lost.
+type ThingerSupervisor struct {
+	*ThingerRPCClient
+	Supervisor *runtime.Supervisor
+}
+
+// NewThingerSupervisor starts a plugin process configured by factory and dispenses the
+// named plugin from it. Failed calls are retried according to policy, after
+// restarting the plugin process if needed.
+func NewThingerSupervisor(name string, factory func() *goplugin.ClientConfig, policy runtime.RetryPolicy) (*ThingerSupervisor, error) {
+	supervisor := runtime.NewSupervisor(name, factory)
+
+	raw, err := supervisor.Start(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	client, ok := raw.(*ThingerRPCClient)
+	if !ok {
+		supervisor.Kill()
+		return nil, fmt.Errorf("plugin %q dispensed %T, not *ThingerRPCClient", name, raw)
+	}
+
+	return &ThingerSupervisor{
+		Supervisor:       supervisor,
+		ThingerRPCClient: client,
+	}, nil
+}
+
+var _ example.Thinger = (*ThingerSupervisor)(nil)
+
+// ThingerReplaceP1 names an untyped interface. It should not be used directly.
+type ThingerReplaceP1 interface {
+	Replace(string) string
+}
 
-// Z_Interface0Plugin implements the Plugin interface for Z_Interface0.
-type Z_Interface0Plugin struct {
+// ThingerReplaceP1Plugin implements the Plugin interface for ThingerReplaceP1.
+type ThingerReplaceP1Plugin struct {
 	impl interface {
 		Replace(string) string
 	}
 }
 
-func NewZ_Interface0Plugin(impl interface {
+func NewThingerReplaceP1Plugin(impl interface {
 	Replace(string) string
-}) *Z_Interface0Plugin {
-	return &Z_Interface0Plugin{impl: impl}
+}) *ThingerReplaceP1Plugin {
+	return &ThingerReplaceP1Plugin{impl: impl}
 }
 
-var _ goplugin.Plugin = (*Z_Interface0Plugin)(nil) // Compile-time check that Z_Interface0Plugin is a Plugin.
+var _ goplugin.Plugin = (*ThingerReplaceP1Plugin)(nil) // Compile-time check that ThingerReplaceP1Plugin is a Plugin.
 
 // Server implements the Server method for the Plugin interface.
-func (p *Z_Interface0Plugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
-	return NewZ_Interface0RPCServer(b, p.impl), nil
+func (p *ThingerReplaceP1Plugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewThingerReplaceP1RPCServer(b, p.impl), nil
 }
 
 // Client implements the Client method for the Plugin interface.
-func (p *Z_Interface0Plugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
-	return NewZ_Interface0RPCClient(b, c), nil
+func (p *ThingerReplaceP1Plugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewThingerReplaceP1RPCClient(b, c), nil
 }
 
-// Z_Interface0RPCClient implements Z_Interface0 via net/rpc.
-type Z_Interface0RPCClient struct {
-	broker *goplugin.MuxBroker
-	client *rpc.Client
+// ThingerReplaceP1RPCClient implements ThingerReplaceP1 via net/rpc.
+type ThingerReplaceP1RPCClient struct {
+	client *runtime.Client
 }
 
-func NewZ_Interface0RPCClient(b *goplugin.MuxBroker, c *rpc.Client) *Z_Interface0RPCClient {
-	return &Z_Interface0RPCClient{
-		broker: b,
-		client: c,
-	}
+func NewThingerReplaceP1RPCClient(b *goplugin.MuxBroker, c *rpc.Client) *ThingerReplaceP1RPCClient {
+	return &ThingerReplaceP1RPCClient{client: runtime.NewClient("ThingerReplaceP1", b, c, runtime.FatalError)}
 }
 
 var _ interface {
 	Replace(string) string
-} = (*Z_Interface0RPCClient)(nil)
+} = (*ThingerReplaceP1RPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *ThingerReplaceP1RPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *ThingerReplaceP1RPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
 
-// Z_Interface0RPCServer implements the net/rpc server for Z_Interface0.
-type Z_Interface0RPCServer struct {
+// ThingerReplaceP1RPCServer implements the net/rpc server for ThingerReplaceP1.
+type ThingerReplaceP1RPCServer struct {
 	broker *goplugin.MuxBroker
 	impl   interface {
 		Replace(string) string
 	}
 }
 
-func NewZ_Interface0RPCServer(b *goplugin.MuxBroker, impl interface {
+func NewThingerReplaceP1RPCServer(b *goplugin.MuxBroker, impl interface {
 	Replace(string) string
-}) *Z_Interface0RPCServer {
-	return &Z_Interface0RPCServer{
+}) *ThingerReplaceP1RPCServer {
+	return &ThingerReplaceP1RPCServer{
 		broker: b,
 		impl:   impl,
 	}
 }
 
-// Z_Z_Interface0_ReplaceParams contains parameters for the Replace function.
+// Z_ThingerReplaceP1_ReplaceParams contains parameters for the Replace function.
 // It is exported for compatibility with net/rpc and should not be used directly.
-type Z_Z_Interface0_ReplaceParams struct {
+type Z_ThingerReplaceP1_ReplaceParams struct {
 	P0 string
 }
 
-// Z_Z_Interface0_ReplaceResults contains results for the Replace function.
+// Z_ThingerReplaceP1_ReplaceResults contains results for the Replace function.
 // It is exported for compatibility with net/rpc and should not be used directly.
-type Z_Z_Interface0_ReplaceResults struct {
+type Z_ThingerReplaceP1_ReplaceResults struct {
 	R0 string
 }
 
-// Replace implements Replace for the Z_Interface0 interface.
-func (c *Z_Interface0RPCClient) Replace(p0 string) string {
-	params := &Z_Z_Interface0_ReplaceParams{P0: p0}
-	results := &Z_Z_Interface0_ReplaceResults{}
+// Replace implements Replace for the ThingerReplaceP1 interface.
+func (c *ThingerReplaceP1RPCClient) Replace(p0 string) string {
+	params := &Z_ThingerReplaceP1_ReplaceParams{P0: p0}
+	results := &Z_ThingerReplaceP1_ReplaceResults{}
 
-	if err := c.client.Call("Plugin.Replace", params, results); err != nil {
-		log.Fatalln("RPC call to Z_Interface0.Replace failed:", err.Error())
-	}
+	c.client.Call("Replace", params, results)
 
 	return results.R0
 }
 
 // Replace implements the server side of net/rpc calls to Replace.
-func (s *Z_Interface0RPCServer) Replace(params *Z_Z_Interface0_ReplaceParams, results *Z_Z_Interface0_ReplaceResults) error {
+func (s *ThingerReplaceP1RPCServer) Replace(params *Z_ThingerReplaceP1_ReplaceParams, results *Z_ThingerReplaceP1_ReplaceResults) (err error) {
+	defer runtime.Recover("ThingerReplaceP1.Replace", &err)
+
 	r0 := s.impl.Replace(params.P0)
 
 	results.R0 = r0
 
 	return nil
 }
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_ThingerReplaceP1_ReplaceParams", &Z_ThingerReplaceP1_ReplaceParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_ThingerReplaceP1_ReplaceResults", &Z_ThingerReplaceP1_ReplaceResults{})
+}
+
+// ThingerReplaceP1Batch queues calls to ThingerReplaceP1, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type ThingerReplaceP1Batch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new ThingerReplaceP1Batch which sends its calls through c.
+func (c *ThingerReplaceP1RPCClient) Batch() *ThingerReplaceP1Batch {
+	return &ThingerReplaceP1Batch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *ThingerReplaceP1Batch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *ThingerReplaceP1Batch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// Replace queues a call to Replace.
+// The returned results are filled in by Flush.
+func (b *ThingerReplaceP1Batch) Replace(p0 string) *Z_ThingerReplaceP1_ReplaceResults {
+	params := &Z_ThingerReplaceP1_ReplaceParams{P0: p0}
+	results := &Z_ThingerReplaceP1_ReplaceResults{}
+	b.batch.Add("Replace", params, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *ThingerReplaceP1RPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "Replace":
+			results := &Z_ThingerReplaceP1_ReplaceResults{}
+			return results, s.Replace(call.Params.(*Z_ThingerReplaceP1_ReplaceParams), results)
+		}
+		return nil, fmt.Errorf("ThingerReplaceP1.%s cannot be batched", call.Method)
+	})
+}
 
 // ReaderPlugin implements the Plugin interface for Reader.
 type ReaderPlugin struct {
@@ -437,19 +612,26 @@ func (p *ReaderPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}
 
 // ReaderRPCClient implements Reader via net/rpc.
 type ReaderRPCClient struct {
-	broker *goplugin.MuxBroker
-	client *rpc.Client
+	client *runtime.Client
 }
 
 func NewReaderRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *ReaderRPCClient {
-	return &ReaderRPCClient{
-		broker: b,
-		client: c,
-	}
+	return &ReaderRPCClient{client: runtime.NewClient("Reader", b, c, runtime.FatalError)}
 }
 
 var _ io.Reader = (*ReaderRPCClient)(nil)
 
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *ReaderRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *ReaderRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
 // ReaderRPCServer implements the net/rpc server for Reader.
 type ReaderRPCServer struct {
 	broker *goplugin.MuxBroker
@@ -477,30 +659,76 @@ type Z_Reader_ReadResults struct {
 }
 
 // Read implements Read for the Reader interface.
-func (c *ReaderRPCClient) Read(p0 []byte) (int, error) {
-	params := &Z_Reader_ReadParams{P0: p0}
+func (c *ReaderRPCClient) Read(p []byte) (int, error) {
+	params := &Z_Reader_ReadParams{P0: p}
 	results := &Z_Reader_ReadResults{}
 
-	if err := c.client.Call("Plugin.Read", params, results); err != nil {
-		log.Fatalln("RPC call to Reader.Read failed:", err.Error())
-	}
+	c.client.Call("Read", params, results)
 
 	return results.R0, results.R1
 }
 
 // Read implements the server side of net/rpc calls to Read.
-func (s *ReaderRPCServer) Read(params *Z_Reader_ReadParams, results *Z_Reader_ReadResults) error {
+func (s *ReaderRPCServer) Read(params *Z_Reader_ReadParams, results *Z_Reader_ReadResults) (err error) {
+	defer runtime.Recover("Reader.Read", &err)
+
 	r0, r1 := s.impl.Read(params.P0)
 
 	results.R0 = r0
-	if r1 == nil {
-		results.R1 = nil
-	} else {
-		results.R1 = goplugin.NewBasicError(r1)
-	}
+	results.R1 = runtime.WrapError(r1)
 
 	return nil
 }
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Reader_ReadParams", &Z_Reader_ReadParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Reader_ReadResults", &Z_Reader_ReadResults{})
+}
+
+// ReaderBatch queues calls to Reader, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type ReaderBatch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new ReaderBatch which sends its calls through c.
+func (c *ReaderRPCClient) Batch() *ReaderBatch {
+	return &ReaderBatch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *ReaderBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *ReaderBatch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// Read queues a call to Read.
+// The returned results are filled in by Flush.
+func (b *ReaderBatch) Read(p []byte) *Z_Reader_ReadResults {
+	params := &Z_Reader_ReadParams{P0: p}
+	results := &Z_Reader_ReadResults{}
+	b.batch.Add("Read", params, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *ReaderRPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "Read":
+			results := &Z_Reader_ReadResults{}
+			return results, s.Read(call.Params.(*Z_Reader_ReadParams), results)
+		}
+		return nil, fmt.Errorf("Reader.%s cannot be batched", call.Method)
+	})
+}
 
 // WriterPlugin implements the Plugin interface for Writer.
 type WriterPlugin struct {
@@ -525,19 +753,26 @@ func (p *WriterPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}
 
 // WriterRPCClient implements Writer via net/rpc.
 type WriterRPCClient struct {
-	broker *goplugin.MuxBroker
-	client *rpc.Client
+	client *runtime.Client
 }
 
 func NewWriterRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *WriterRPCClient {
-	return &WriterRPCClient{
-		broker: b,
-		client: c,
-	}
+	return &WriterRPCClient{client: runtime.NewClient("Writer", b, c, runtime.FatalError)}
 }
 
 var _ io.Writer = (*WriterRPCClient)(nil)
 
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *WriterRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *WriterRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
 // WriterRPCServer implements the net/rpc server for Writer.
 type WriterRPCServer struct {
 	broker *goplugin.MuxBroker
@@ -565,30 +800,76 @@ type Z_Writer_WriteResults struct {
 }
 
 // Write implements Write for the Writer interface.
-func (c *WriterRPCClient) Write(p0 []byte) (int, error) {
-	params := &Z_Writer_WriteParams{P0: p0}
+func (c *WriterRPCClient) Write(p []byte) (int, error) {
+	params := &Z_Writer_WriteParams{P0: p}
 	results := &Z_Writer_WriteResults{}
 
-	if err := c.client.Call("Plugin.Write", params, results); err != nil {
-		log.Fatalln("RPC call to Writer.Write failed:", err.Error())
-	}
+	c.client.Call("Write", params, results)
 
 	return results.R0, results.R1
 }
 
 // Write implements the server side of net/rpc calls to Write.
-func (s *WriterRPCServer) Write(params *Z_Writer_WriteParams, results *Z_Writer_WriteResults) error {
+func (s *WriterRPCServer) Write(params *Z_Writer_WriteParams, results *Z_Writer_WriteResults) (err error) {
+	defer runtime.Recover("Writer.Write", &err)
+
 	r0, r1 := s.impl.Write(params.P0)
 
 	results.R0 = r0
-	if r1 == nil {
-		results.R1 = nil
-	} else {
-		results.R1 = goplugin.NewBasicError(r1)
-	}
+	results.R1 = runtime.WrapError(r1)
 
 	return nil
 }
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Writer_WriteParams", &Z_Writer_WriteParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/example/exampleplug.Z_Writer_WriteResults", &Z_Writer_WriteResults{})
+}
+
+// WriterBatch queues calls to Writer, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type WriterBatch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new WriterBatch which sends its calls through c.
+func (c *WriterRPCClient) Batch() *WriterBatch {
+	return &WriterBatch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *WriterBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *WriterBatch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// Write queues a call to Write.
+// The returned results are filled in by Flush.
+func (b *WriterBatch) Write(p []byte) *Z_Writer_WriteResults {
+	params := &Z_Writer_WriteParams{P0: p}
+	results := &Z_Writer_WriteResults{}
+	b.batch.Add("Write", params, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *WriterRPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "Write":
+			results := &Z_Writer_WriteResults{}
+			return results, s.Write(call.Params.(*Z_Writer_WriteParams), results)
+		}
+		return nil, fmt.Errorf("Writer.%s cannot be batched", call.Method)
+	})
+}
 
 // PluginHandshake is a plugin handshake generated from the input interfaces.
 var PluginHandshake = goplugin.HandshakeConfig{
@@ -596,7 +877,3 @@ var PluginHandshake = goplugin.HandshakeConfig{
 	MagicCookieValue: "ee2f63579676392f535cbdeb674657fc",
 	ProtocolVersion:  1,
 }
-
-func init() {
-	gob.Register(&goplugin.BasicError{})
-}
@@ -0,0 +1,154 @@
+// Code generated by "plugingen -type=Thinger -subpkg=exampleplug -panicrpc -supervisor -batch -docs -mocks -conformance ."; DO NOT EDIT.
+
+package exampleplug
+
+import (
+	example "github.com/jakebailey/plugingen/example"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	"io"
+)
+
+// ThingerMock is a mock implementation of Thinger.
+// Calls are recorded in Calls, then handled by the matching function
+// field if set, or else return zero values.
+type ThingerMock struct {
+	CopyFunc         func(p0 io.Writer, p1 io.Reader) (int64, error)
+	DoNothingFunc    func()
+	ErrorToErrorFunc func(p0 error) error
+	IdentityFunc     func(p0 interface{}) interface{}
+	ReplaceFunc      func(p0 string, p1 interface {
+		Replace(string) string
+	}) string
+	StringFunc func() string
+	SumFunc    func(p0 ...int) int
+
+	Calls runtime.MockRecorder
+}
+
+var _ example.Thinger = (*ThingerMock)(nil)
+
+// Copy implements Copy for the Thinger interface.
+func (m *ThingerMock) Copy(p0 io.Writer, p1 io.Reader) (r0 int64, r1 error) {
+	m.Calls.Record("Copy", p0, p1)
+	if m.CopyFunc != nil {
+		return m.CopyFunc(p0, p1)
+	}
+	return
+}
+
+// DoNothing implements DoNothing for the Thinger interface.
+func (m *ThingerMock) DoNothing() {
+	m.Calls.Record("DoNothing")
+	if m.DoNothingFunc != nil {
+		m.DoNothingFunc()
+	}
+}
+
+// ErrorToError implements ErrorToError for the Thinger interface.
+func (m *ThingerMock) ErrorToError(p0 error) (r0 error) {
+	m.Calls.Record("ErrorToError", p0)
+	if m.ErrorToErrorFunc != nil {
+		return m.ErrorToErrorFunc(p0)
+	}
+	return
+}
+
+// Identity implements Identity for the Thinger interface.
+func (m *ThingerMock) Identity(p0 interface{}) (r0 interface{}) {
+	m.Calls.Record("Identity", p0)
+	if m.IdentityFunc != nil {
+		return m.IdentityFunc(p0)
+	}
+	return
+}
+
+// Replace implements Replace for the Thinger interface.
+func (m *ThingerMock) Replace(p0 string, p1 interface {
+	Replace(string) string
+}) (r0 string) {
+	m.Calls.Record("Replace", p0, p1)
+	if m.ReplaceFunc != nil {
+		return m.ReplaceFunc(p0, p1)
+	}
+	return
+}
+
+// String implements String for the Thinger interface.
+func (m *ThingerMock) String() (r0 string) {
+	m.Calls.Record("String")
+	if m.StringFunc != nil {
+		return m.StringFunc()
+	}
+	return
+}
+
+// Sum implements Sum for the Thinger interface.
+func (m *ThingerMock) Sum(p0 ...int) (r0 int) {
+	m.Calls.Record("Sum", p0)
+	if m.SumFunc != nil {
+		return m.SumFunc(p0...)
+	}
+	return
+}
+
+// ThingerReplaceP1Mock is a mock implementation of ThingerReplaceP1.
+// Calls are recorded in Calls, then handled by the matching function
+// field if set, or else return zero values.
+type ThingerReplaceP1Mock struct {
+	ReplaceFunc func(p0 string) string
+
+	Calls runtime.MockRecorder
+}
+
+var _ interface {
+	Replace(string) string
+} = (*ThingerReplaceP1Mock)(nil)
+
+// Replace implements Replace for the ThingerReplaceP1 interface.
+func (m *ThingerReplaceP1Mock) Replace(p0 string) (r0 string) {
+	m.Calls.Record("Replace", p0)
+	if m.ReplaceFunc != nil {
+		return m.ReplaceFunc(p0)
+	}
+	return
+}
+
+// ReaderMock is a mock implementation of Reader.
+// Calls are recorded in Calls, then handled by the matching function
+// field if set, or else return zero values.
+type ReaderMock struct {
+	ReadFunc func(p []byte) (int, error)
+
+	Calls runtime.MockRecorder
+}
+
+var _ io.Reader = (*ReaderMock)(nil)
+
+// Read implements Read for the Reader interface.
+func (m *ReaderMock) Read(p []byte) (r0 int, r1 error) {
+	m.Calls.Record("Read", p)
+	if m.ReadFunc != nil {
+		return m.ReadFunc(p)
+	}
+	return
+}
+
+// WriterMock is a mock implementation of Writer.
+// Calls are recorded in Calls, then handled by the matching function
+// field if set, or else return zero values.
+type WriterMock struct {
+	WriteFunc func(p []byte) (int, error)
+
+	Calls runtime.MockRecorder
+}
+
+var _ io.Writer = (*WriterMock)(nil)
+
+// Write implements Write for the Writer interface.
+func (m *WriterMock) Write(p []byte) (r0 int, r1 error) {
+	m.Calls.Record("Write", p)
+	if m.WriteFunc != nil {
+		return m.WriteFunc(p)
+	}
+	return
+}
@@ -0,0 +1,46 @@
+// Code generated by "plugingen -type=Thinger -subpkg=exampleplug -panicrpc -supervisor -batch -docs -mocks -conformance ."; DO NOT EDIT.
+
+package exampleplug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	example "github.com/jakebailey/plugingen/example"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	"testing"
+)
+
+// ConformThinger checks that calls made to impl through ThingerRPCClient
+// return the same results as calls made to impl directly. Each of calls is
+// made both ways, and returns the results of the methods it calls. Methods
+// of Thinger without parameters are always checked.
+func ConformThinger(t *testing.T, impl example.Thinger, calls ...func(example.Thinger) []interface{}) {
+	t.Helper()
+
+	client, _ := goplugin.TestPluginRPCConn(t, map[string]goplugin.Plugin{"plugin": NewThingerPlugin(impl)}, nil)
+	defer client.Close()
+
+	raw, err := client.Dispense("plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := raw.(example.Thinger)
+
+	calls = append([]func(example.Thinger) []interface{}{
+		func(x example.Thinger) []interface{} {
+			x.DoNothing()
+			return nil
+		},
+		func(x example.Thinger) []interface{} {
+			r0 := x.String()
+			return []interface{}{r0}
+		},
+	}, calls...)
+
+	for i, call := range calls {
+		want := call(impl)
+		got := call(remote)
+		if diff := runtime.CompareResults(want, got); diff != "" {
+			t.Errorf("call %d: %s", i, diff)
+		}
+	}
+}
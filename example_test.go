@@ -1,20 +1,69 @@
-package main
+package plugingen
 
 import (
-	"os"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
+
+	"github.com/jakebailey/plugingen/example/exampleplug"
 )
 
+// TestExample regenerates the example with the flags of its go:generate
+// directive, and compares the output to the committed files.
 func TestExample(t *testing.T) {
-	params := runParams{
-		typeList: []string{"Thinger"},
-		output:   os.DevNull,
-		subPkg:   "exampleplug",
-		rpcPanic: true,
-		args:     []string{"./example"},
+	config := Config{
+		Types:       []string{"Thinger"},
+		SubPkg:      "exampleplug",
+		RPCPanic:    true,
+		Supervisor:  true,
+		Batch:       true,
+		Docs:        true,
+		Mocks:       true,
+		Conformance: true,
+		Args:        []string{"./example"},
+		Command:     "plugingen -type=Thinger -subpkg=exampleplug -panicrpc -supervisor -batch -docs -mocks -conformance .",
+	}
+
+	files, _, err := Generate(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := files[filepath.Join("example", "exampleplug", "plugingen.go")]; !ok {
+		t.Fatal("Generate() did not produce example/exampleplug/plugingen.go")
+	}
+
+	for name, got := range files {
+		want, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s differs from generated output, first at line %d; run go generate in example", name, firstDiff(got, want))
+		}
+	}
+}
+
+func TestExampleSchema(t *testing.T) {
+	config := Config{
+		Types: []string{"Thinger"},
+		Args:  []string{"./example"},
+	}
+
+	sch, _, err := Schema(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := sch.Handshake.MagicCookieValue, exampleplug.PluginHandshake.MagicCookieValue; got != want {
+		t.Errorf("schema handshake cookie = %v; want %v", got, want)
 	}
 
-	if err := run(params); err != nil {
-		t.Error(err)
+	if got := sch.Interfaces[0]; got.Name != "Thinger" || !got.Root {
+		t.Errorf("first schema interface = %v (root %v); want root Thinger", got.Name, got.Root)
 	}
 }
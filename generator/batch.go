@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/jakebailey/plugingen/analyzer"
+)
+
+// batchable reports whether calls to m can be sent in a batch. Brokered
+// interface parameters need a connection per call, so they are excluded.
+func batchable(m *analyzer.Method) bool {
+	if m.Skip {
+		return false
+	}
+
+	for _, param := range m.Params {
+		if param.IFace != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (gen *Generator) generateBatch(iface *analyzer.Interface) {
+	interfaceName, _ := gen.interfaceName(iface)
+	clientName := gen.clientName(iface)
+	serverName := gen.serverName(iface)
+	batchName := gen.batchName(iface)
+
+	var methods []*analyzer.Method
+	for _, m := range iface.Methods {
+		if batchable(m) {
+			methods = append(methods, m)
+		}
+	}
+
+	if len(methods) == 0 {
+		return
+	}
+
+	gen.file.Func().Id("init").Params().BlockFunc(func(g *jen.Group) {
+		for _, m := range methods {
+			if len(m.Params) != 0 {
+				g.Add(gen.gobRegister(gen.paramsStructName(iface, m)))
+			}
+			if len(m.Results) != 0 {
+				g.Add(gen.gobRegister(gen.resultsStructName(iface, m)))
+			}
+		}
+	})
+
+	gen.file.Commentf("%s queues calls to %s, sending them to the plugin in a single RPC", batchName, interfaceName)
+	gen.file.Comment("when flushed. Methods with interface parameters cannot be batched.")
+	gen.file.Type().Id(batchName).Struct(
+		jen.Id("client").Op("*").Qual(runtimePath, "Client"),
+		jen.Id("batch").Qual(runtimePath, "Batch"),
+	)
+
+	gen.file.Commentf("Batch returns a new %s which sends its calls through c.", batchName)
+	gen.file.Func().
+		Params(jen.Id("c").Op("*").Id(clientName)).
+		Id("Batch").
+		Params().
+		Op("*").Id(batchName).
+		Block(jen.Return(jen.Op("&").Id(batchName).Values(jen.Dict{
+			jen.Id("client"): jen.Id("c").Dot("client"),
+		})))
+
+	gen.file.Comment("Len returns the number of queued calls.")
+	gen.file.Func().
+		Params(jen.Id("b").Op("*").Id(batchName)).
+		Id("Len").
+		Params().
+		Int().
+		Block(jen.Return(jen.Id("b").Dot("batch").Dot("Len").Call()))
+
+	gen.file.Comment("Flush sends the queued calls to the plugin, filling in their results.")
+	gen.file.Comment("The calls are executed in order; if one fails, its error is returned and")
+	gen.file.Comment("the calls after it are not executed.")
+	gen.file.Func().
+		Params(jen.Id("b").Op("*").Id(batchName)).
+		Id("Flush").
+		Params().
+		Error().
+		Block(jen.Return(jen.Id("b").Dot("client").Dot("Flush").Call(jen.Op("&").Id("b").Dot("batch"))))
+
+	for _, m := range methods {
+		gen.generateBatchMethod(iface, m)
+	}
+
+	gen.file.Comment("Z_Batch implements the server side of batched calls.")
+	gen.file.Comment("It is exported for compatibility with net/rpc and should not be used directly.")
+	gen.file.Func().
+		Params(jen.Id("s").Op("*").Id(serverName)).
+		Id("Z_Batch").
+		Params(
+			jen.Id("calls").Index().Qual(runtimePath, "BatchCall"),
+			jen.Id("results").Op("*").Index().Qual(runtimePath, "BatchResult"),
+		).
+		Error().
+		Block(jen.Return(jen.Qual(runtimePath, "ServeBatch").Call(
+			jen.Id("calls"),
+			jen.Id("results"),
+			jen.Func().
+				Params(jen.Id("call").Qual(runtimePath, "BatchCall")).
+				Params(jen.Interface(), jen.Error()).
+				Block(
+					jen.Switch(jen.Id("call").Dot("Method")).BlockFunc(func(g *jen.Group) {
+						for _, m := range methods {
+							g.Case(jen.Lit(m.Name)).BlockFunc(func(g *jen.Group) {
+								params := jen.Nil()
+								if len(m.Params) != 0 {
+									params = jen.Id("call").Dot("Params").Assert(jen.Op("*").Id(gen.paramsStructName(iface, m)))
+								}
+
+								if len(m.Results) == 0 {
+									g.Return(jen.Nil(), jen.Id("s").Dot(gen.serverMethodName(m)).Call(params, jen.Nil()))
+									return
+								}
+
+								g.Id(resultsStructID).Op(":=").Op("&").Id(gen.resultsStructName(iface, m)).Values()
+								g.Return(jen.Id(resultsStructID), jen.Id("s").Dot(gen.serverMethodName(m)).Call(params, jen.Id(resultsStructID)))
+							})
+						}
+					}),
+					jen.Return(jen.Nil(), jen.Qual("fmt", "Errorf").Call(jen.Lit(interfaceName+".%s cannot be batched"), jen.Id("call").Dot("Method"))),
+				),
+		)))
+}
+
+func (gen *Generator) generateBatchMethod(iface *analyzer.Interface, m *analyzer.Method) {
+	batchName := gen.batchName(iface)
+	paramsStructName := gen.paramsStructName(iface, m)
+	resultsStructName := gen.resultsStructName(iface, m)
+
+	gen.file.Commentf("%s queues a call to %s.", m.Name, m.Name)
+	if len(m.Results) != 0 {
+		gen.file.Comment("The returned results are filled in by Flush.")
+	}
+	gen.file.Func().
+		Params(jen.Id("b").Op("*").Id(batchName)).
+		Id(m.Name).
+		Add(gen.batchMethodSignature(iface, m)).
+		BlockFunc(func(g *jen.Group) {
+			params := jen.Nil()
+			if len(m.Params) != 0 {
+				g.Id(paramsStructID).Op(":=").
+					Op("&").Id(paramsStructName).
+					Add(gen.clientParamsValues(m))
+				params = jen.Id(paramsStructID)
+			}
+
+			results := jen.Nil()
+			if len(m.Results) != 0 {
+				g.Id(resultsStructID).Op(":=").Op("&").Id(resultsStructName).Values()
+				results = jen.Id(resultsStructID)
+			}
+
+			g.Id("b").Dot("batch").Dot("Add").Call(jen.Lit(m.Name), params, results)
+
+			if len(m.Results) != 0 {
+				g.Return(jen.Id(resultsStructID))
+			}
+		})
+}
+
+func (gen *Generator) batchMethodSignature(iface *analyzer.Interface, m *analyzer.Method) *jen.Statement {
+	if len(m.Results) == 0 {
+		return gen.clientMethodParams(m)
+	}
+	return gen.clientMethodParams(m).Op("*").Id(gen.resultsStructName(iface, m))
+}
+
+// gobRegister registers a pointer to the named type with gob, under a name
+// qualified by the generated package's import path, as gob.Register would
+// only qualify it by the package's name.
+func (gen *Generator) gobRegister(name string) *jen.Statement {
+	value := jen.Op("&").Id(name).Values()
+	if gen.opts.PkgPath == "" {
+		return jen.Qual("encoding/gob", "Register").Call(value)
+	}
+	return jen.Qual("encoding/gob", "RegisterName").Call(jen.Lit("*"+gen.opts.PkgPath+"."+name), value)
+}
@@ -0,0 +1,194 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/jakebailey/plugingen/analyzer"
+	"github.com/jakebailey/plugingen/tojen"
+)
+
+// generateCapabilities generates capability discovery for the optional
+// interfaces of iface: a Z_Capabilities server method which serves the
+// optional interfaces the implementation satisfies, and a WithCapabilities
+// client method which returns a client implementing exactly those.
+func (gen *Generator) generateCapabilities(iface *analyzer.Interface) {
+	if len(iface.Optional) == 0 {
+		return
+	}
+
+	interfaceName, _ := gen.interfaceName(iface)
+	clientName := gen.clientName(iface)
+	serverName := gen.serverName(iface)
+
+	codec := jen.Lit("")
+	if gen.opts.Codec != "" {
+		codec = jen.Id("pluginCodec")
+	}
+
+	gen.file.Comment("Z_Capabilities serves each optional interface implemented by s.impl on a")
+	gen.file.Comment("new connection, storing the connection IDs by interface name in caps.")
+	gen.file.Comment("It is exported for compatibility with net/rpc and should not be used directly.")
+	gen.file.Func().
+		Params(jen.Id("s").Op("*").Id(serverName)).
+		Id("Z_Capabilities").
+		Params(
+			jen.Id("_").Interface(),
+			jen.Id("caps").Op("*").Map(jen.String()).Uint32(),
+		).
+		Error().
+		BlockFunc(func(g *jen.Group) {
+			g.Op("*").Id("caps").Op("=").Map(jen.String()).Uint32().Values()
+
+			for _, opt := range iface.Optional {
+				optName, _ := gen.interfaceName(opt)
+				g.If(
+					jen.List(jen.Id("impl"), jen.Id("ok")).Op(":=").Id("s").Dot("impl").Assert(tojen.Type(opt.Typ)),
+					jen.Id("ok"),
+				).Block(
+					jen.Parens(jen.Op("*").Id("caps")).Index(jen.Lit(optName)).Op("=").Qual(runtimePath, "Serve").Call(
+						jen.Id("s").Dot("broker"),
+						jen.Id("New"+gen.serverName(opt)).Call(jen.Id("s").Dot("broker"), jen.Id("impl")),
+						codec,
+					),
+				)
+			}
+
+			g.Return(jen.Nil())
+		})
+
+	gen.file.Commentf("WithCapabilities asks the plugin which optional interfaces its %s", interfaceName)
+	gen.file.Comment("implements, returning a client which implements exactly those as well.")
+	gen.file.Comment("If it implements none of them, c is returned. The optional interfaces'")
+	gen.file.Comment("connections are closed when c's is replaced, then dialed again.")
+	gen.file.Func().
+		Params(jen.Id("c").Op("*").Id(clientName)).
+		Id("WithCapabilities").
+		Params().
+		Params(tojen.Type(iface.Typ), jen.Error()).
+		BlockFunc(func(g *jen.Group) {
+			g.Var().Id("caps").Map(jen.String()).Uint32()
+			g.If(
+				jen.Err().Op(":=").Id("c").Dot("client").Dot("Call").Call(jen.Lit("Z_Capabilities"), jen.Nil(), jen.Op("&").Id("caps")),
+				jen.Err().Op("!=").Nil(),
+			).Block(jen.Return(jen.Nil(), jen.Err()))
+			g.Line()
+
+			g.Var().Id("mask").Int()
+			for i, opt := range iface.Optional {
+				optName, _ := gen.interfaceName(opt)
+				g.Var().Id(optionalName(i)).Op("*").Id(gen.clientName(opt))
+				g.If(
+					jen.List(jen.Id("id"), jen.Id("ok")).Op(":=").Id("caps").Index(jen.Lit(optName)),
+					jen.Id("ok"),
+				).Block(
+					jen.List(jen.Id("conn"), jen.Err()).Op(":=").Id("c").Dot("client").Dot("Dial").Call(jen.Id("id")),
+					jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Nil(), jen.Err())),
+					jen.Id(optionalName(i)).Op("=").Id("New"+gen.clientName(opt)).Call(
+						jen.Id("c").Dot("client").Dot("Broker").Call(),
+						jen.Id("conn"),
+					),
+					jen.Id("c").Dot("client").Dot("Attach").Call(jen.Lit(optName), jen.Id(optionalName(i)).Dot("client")),
+					jen.Id("mask").Op("|=").Lit(1<<i),
+				)
+			}
+			g.Line()
+
+			g.Switch(jen.Id("mask")).BlockFunc(func(g *jen.Group) {
+				for mask := 1; mask < 1<<len(iface.Optional); mask++ {
+					g.Case(jen.Lit(mask)).Block(jen.Return(
+						jen.Op("&").Id(gen.capabilitiesName(iface, mask)).ValuesFunc(func(g *jen.Group) {
+							g.Id("c")
+							for i := range iface.Optional {
+								if mask&(1<<i) != 0 {
+									g.Id(optionalName(i))
+								}
+							}
+						}),
+						jen.Nil(),
+					))
+				}
+			})
+			g.Return(jen.Id("c"), jen.Nil())
+		})
+
+	for mask := 1; mask < 1<<len(iface.Optional); mask++ {
+		gen.generateCapabilitiesClient(iface, mask)
+	}
+}
+
+// generateCapabilitiesClient generates the client returned by
+// WithCapabilities when the plugin implements the optional interfaces of
+// iface selected by mask.
+func (gen *Generator) generateCapabilitiesClient(iface *analyzer.Interface, mask int) {
+	name := gen.capabilitiesName(iface, mask)
+
+	members := []*analyzer.Interface{iface}
+	for i, opt := range iface.Optional {
+		if mask&(1<<i) != 0 {
+			members = append(members, opt)
+		}
+	}
+
+	var names []string
+	for _, member := range members {
+		optName, _ := gen.interfaceName(member)
+		names = append(names, optName)
+	}
+
+	gen.file.Commentf("%s implements %s.", name, strings.Join(names, ", "))
+	gen.file.Type().Id(name).StructFunc(func(g *jen.Group) {
+		for _, member := range members {
+			g.Op("*").Id(gen.clientName(member))
+		}
+	})
+
+	for _, member := range members[1:] {
+		gen.file.Var().Id("_").Add(tojen.Type(member.Typ)).Op("=").
+			Parens(jen.Op("*").Id(name)).Parens(jen.Nil())
+	}
+
+	// Methods provided by more than one member would be ambiguous, so they
+	// are forwarded to the first member providing them.
+	provider := map[string]*analyzer.Interface{}
+	providers := map[string]int{}
+	var methods []*analyzer.Method
+	for _, member := range members {
+		for _, m := range member.Methods {
+			providers[m.Name]++
+			if _, ok := provider[m.Name]; !ok {
+				provider[m.Name] = member
+				methods = append(methods, m)
+			}
+		}
+	}
+
+	for _, m := range methods {
+		if providers[m.Name] == 1 {
+			continue
+		}
+
+		call := jen.Id("w").Dot(gen.clientName(provider[m.Name])).Dot(m.Name).CallFunc(func(g *jen.Group) {
+			for i := range m.Params {
+				if m.Variadic && i == len(m.Params)-1 {
+					g.Id(gen.paramName(m, i)).Op("...")
+					continue
+				}
+				g.Id(gen.paramName(m, i))
+			}
+		})
+
+		gen.file.Commentf("%s forwards to %s, as more than one interface has it.", m.Name, gen.clientName(provider[m.Name]))
+		gen.file.Func().
+			Params(jen.Id("w").Op("*").Id(name)).
+			Id(m.Name).
+			Add(gen.clientMethodSignature(m)).
+			BlockFunc(func(g *jen.Group) {
+				if len(m.Results) == 0 {
+					g.Add(call)
+				} else {
+					g.Return(call)
+				}
+			})
+	}
+}
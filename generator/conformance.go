@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/jakebailey/plugingen/analyzer"
+	"github.com/jakebailey/plugingen/tojen"
+)
+
+// GenerateConformance generates a conformance harness for each top-level
+// interface in ifaces, which checks that calls made through the generated
+// RPC client match calls made to an implementation directly. The harnesses
+// refer to the code generated by Generate, and are meant to be rendered into
+// a test file of the same package.
+func (gen *Generator) GenerateConformance(ifaces []*analyzer.Interface) {
+	gen.nameInterfaces(ifaces)
+
+	for _, iface := range ifaces {
+		if iface.TopLevel {
+			gen.generateConformance(iface)
+		}
+	}
+}
+
+func (gen *Generator) generateConformance(iface *analyzer.Interface) {
+	interfaceName, _ := gen.interfaceName(iface)
+	conformName := gen.conformName(iface)
+
+	callType := jen.Func().Params(tojen.Type(iface.Typ)).Index().Interface()
+
+	gen.file.Commentf("%s checks that calls made to impl through %s", conformName, gen.clientName(iface))
+	gen.file.Comment("return the same results as calls made to impl directly. Each of calls is")
+	gen.file.Comment("made both ways, and returns the results of the methods it calls. Methods")
+	gen.file.Commentf("of %s without parameters are always checked.", interfaceName)
+	gen.file.Func().Id(conformName).Params(
+		jen.Id("t").Op("*").Qual("testing", "T"),
+		jen.Id("impl").Add(tojen.Type(iface.Typ)),
+		jen.Id("calls").Op("...").Add(callType),
+	).Block(
+		jen.Id("t").Dot("Helper").Call(),
+		jen.Line(),
+		jen.List(jen.Id("client"), jen.Id("_")).Op(":=").Qual(gopluginPath, "TestPluginRPCConn").Call(
+			jen.Id("t"),
+			jen.Map(jen.String()).Qual(gopluginPath, "Plugin").Values(jen.Dict{
+				jen.Lit("plugin"): jen.Id("New" + gen.pluginName(iface)).Call(jen.Id("impl")),
+			}),
+			jen.Nil(),
+		),
+		jen.Defer().Id("client").Dot("Close").Call(),
+		jen.Line(),
+		jen.List(jen.Id("raw"), jen.Err()).Op(":=").Id("client").Dot("Dispense").Call(jen.Lit("plugin")),
+		jen.If(jen.Err().Op("!=").Nil()).Block(
+			jen.Id("t").Dot("Fatal").Call(jen.Err()),
+		),
+		jen.Id("remote").Op(":=").Id("raw").Assert(tojen.Type(iface.Typ)),
+		jen.Line(),
+		jen.Id("calls").Op("=").Append(
+			jen.Index().Add(callType).ValuesFunc(func(g *jen.Group) {
+				for _, m := range iface.Methods {
+					if m.Skip || len(m.Params) != 0 {
+						continue
+					}
+					g.Line().Add(gen.conformanceCall(iface, m))
+				}
+				g.Line()
+			}),
+			jen.Id("calls").Op("..."),
+		),
+		jen.Line(),
+		jen.For(jen.List(jen.Id("i"), jen.Id("call")).Op(":=").Range().Id("calls")).Block(
+			jen.Id("want").Op(":=").Id("call").Call(jen.Id("impl")),
+			jen.Id("got").Op(":=").Id("call").Call(jen.Id("remote")),
+			jen.If(
+				jen.Id("diff").Op(":=").Qual(runtimePath, "CompareResults").Call(jen.Id("want"), jen.Id("got")),
+				jen.Id("diff").Op("!=").Lit(""),
+			).Block(
+				jen.Id("t").Dot("Errorf").Call(jen.Lit("call %d: %s"), jen.Id("i"), jen.Id("diff")),
+			),
+		),
+	)
+}
+
+// conformanceCall returns a call of m, which has no parameters, for use by
+// the conformance harness.
+func (gen *Generator) conformanceCall(iface *analyzer.Interface, m *analyzer.Method) jen.Code {
+	call := jen.Id("x").Dot(m.Name).Call()
+
+	return jen.Func().Params(jen.Id("x").Add(tojen.Type(iface.Typ))).Index().Interface().BlockFunc(func(g *jen.Group) {
+		if len(m.Results) == 0 {
+			g.Add(call)
+			g.Return(jen.Nil())
+			return
+		}
+
+		g.ListFunc(func(g *jen.Group) {
+			for i := range m.Results {
+				g.Id(resultName(i))
+			}
+		}).Op(":=").Add(call)
+
+		g.Return(jen.Index().Interface().ValuesFunc(func(g *jen.Group) {
+			for i := range m.Results {
+				g.Id(resultName(i))
+			}
+		}))
+	})
+}
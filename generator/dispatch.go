@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/jakebailey/plugingen/analyzer"
+)
+
+// generateDispatch generates the Call method of the server for iface, which
+// decodes calls sent by clients created with runtime.WithDispatch and passes
+// them to the unexported server methods.
+func (gen *Generator) generateDispatch(iface *analyzer.Interface) {
+	interfaceName, _ := gen.interfaceName(iface)
+	serverName := gen.serverName(iface)
+
+	gen.file.Commentf("Call dispatches a call to the named method of %s, decoding its parameters", interfaceName)
+	gen.file.Comment("from and encoding its results into the body. It is exported for compatibility")
+	gen.file.Comment("with net/rpc and should not be used directly.")
+	gen.file.Func().
+		Params(jen.Id("s").Op("*").Id(serverName)).
+		Id("Call").
+		Params(
+			jen.Id("call").Qual(runtimePath, "DispatchCall"),
+			jen.Id("body").Op("*").Index().Byte(),
+		).
+		Params(jen.Id("err").Error()).
+		Block(
+			jen.Switch(jen.Id("call").Dot("Method")).BlockFunc(func(g *jen.Group) {
+				for _, m := range iface.Methods {
+					if m.Skip {
+						continue
+					}
+					g.Case(jen.Lit(m.Name)).BlockFunc(func(g *jen.Group) {
+						gen.generateDispatchCase(g, iface, m)
+					})
+				}
+			}),
+			jen.Line(),
+			jen.Return(jen.Op("&").Qual(runtimePath, "UnknownMethodError").Values(jen.Dict{
+				jen.Id("Method"): jen.Lit(interfaceName + ".").Op("+").Id("call").Dot("Method"),
+			})),
+		)
+}
+
+func (gen *Generator) generateDispatchCase(g *jen.Group, iface *analyzer.Interface, m *analyzer.Method) {
+	params := jen.Nil()
+	if len(m.Params) != 0 {
+		params = jen.Id(paramsStructID)
+		g.Id(paramsStructID).Op(":=").Op("&").Id(gen.paramsStructName(iface, m)).Values()
+		g.If(
+			jen.Err().Op(":=").Qual(runtimePath, "Decode").Call(jen.Id("call").Dot("Body"), jen.Id(paramsStructID)),
+			jen.Err().Op("!=").Nil(),
+		).Block(jen.Return(jen.Err()))
+	}
+
+	serve := jen.Id("s").Dot(gen.serverMethodName(m))
+
+	if len(m.Results) == 0 {
+		g.Return(serve.Call(params, jen.Nil()))
+		return
+	}
+
+	g.Id(resultsStructID).Op(":=").Op("&").Id(gen.resultsStructName(iface, m)).Values()
+	g.If(
+		jen.Err().Op(":=").Add(serve).Call(params, jen.Id(resultsStructID)),
+		jen.Err().Op("!=").Nil(),
+	).Block(jen.Return(jen.Err()))
+	g.List(jen.Op("*").Id("body"), jen.Err()).Op("=").Qual(runtimePath, "Encode").Call(jen.Id(resultsStructID))
+	g.Return(jen.Err())
+}
@@ -0,0 +1,243 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/jakebailey/plugingen/analyzer"
+	"github.com/jakebailey/plugingen/typesext"
+)
+
+// Docs renders Markdown documentation for ifaces, for authors of plugins
+// implementing them. Diagnostics are listed as caveats of their methods.
+// If netrpc is set, the go-plugin handshake is included, along with the
+// retries and timeouts which only generated net/rpc clients apply; otherwise
+// the methods are documented as called over JSON-RPC.
+func (gen *Generator) Docs(command string, ifaces []*analyzer.Interface, diags []analyzer.Diagnostic, netrpc bool) []byte {
+	gen.nameInterfaces(ifaces)
+
+	methodDiags := map[string][]analyzer.Diagnostic{}
+	var otherDiags []analyzer.Diagnostic
+	for _, d := range diags {
+		if d.Method == "" {
+			otherDiags = append(otherDiags, d)
+			continue
+		}
+		methodDiags[d.Method] = append(methodDiags[d.Method], d)
+	}
+
+	// usedBy maps brokered interfaces to the methods taking them,
+	// embeddedIn maps embedded interfaces to the interfaces embedding them,
+	// and optionalFor maps optional interfaces to the interfaces listing them.
+	usedBy := map[*analyzer.Interface][]string{}
+	embeddedIn := map[*analyzer.Interface][]string{}
+	optionalFor := map[*analyzer.Interface][]string{}
+	for _, iface := range ifaces {
+		name, _ := gen.interfaceName(iface)
+		for _, e := range iface.Embeds {
+			embeddedIn[e] = append(embeddedIn[e], name)
+		}
+		for _, opt := range iface.Optional {
+			optionalFor[opt] = append(optionalFor[opt], name)
+		}
+		for _, m := range iface.Methods {
+			for _, p := range m.Params {
+				if p.IFace != nil {
+					usedBy[p.IFace] = append(usedBy[p.IFace], name+"."+m.Name)
+				}
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "<!-- Code generated by \"%s\"; DO NOT EDIT. -->\n\n", command)
+	fmt.Fprintf(buf, "# Plugin API\n")
+
+	if netrpc {
+		fmt.Fprintf(buf, "\n## Handshake\n\n")
+		fmt.Fprintf(buf, "| Field | Value |\n|---|---|\n")
+		fmt.Fprintf(buf, "| ProtocolVersion | `1` |\n")
+		fmt.Fprintf(buf, "| MagicCookieKey | `%s` |\n", magicCookieKey)
+		fmt.Fprintf(buf, "| MagicCookieValue | `%s` |\n", Handshake(ifaces))
+	}
+
+	fmt.Fprintf(buf, "\n## Interfaces\n")
+
+	for _, iface := range ifaces {
+		name, _ := gen.interfaceName(iface)
+
+		fmt.Fprintf(buf, "\n### %s\n\n", name)
+
+		if iface.Doc != "" {
+			fmt.Fprintf(buf, "%s\n", iface.Doc)
+		}
+
+		if iface.TopLevel {
+			fmt.Fprintf(buf, "Implemented by the plugin.\n")
+		}
+
+		if users := usedBy[iface]; len(users) != 0 {
+			sort.Strings(users)
+			fmt.Fprintf(buf, "Brokered when passed to %s.\n", strings.Join(users, ", "))
+		}
+
+		if embedders := embeddedIn[iface]; len(embedders) != 0 {
+			sort.Strings(embedders)
+			fmt.Fprintf(buf, "Embedded in %s.\n", strings.Join(embedders, ", "))
+		}
+
+		if len(iface.Optional) != 0 {
+			var names []string
+			for _, opt := range iface.Optional {
+				optName, _ := gen.interfaceName(opt)
+				names = append(names, optName)
+			}
+			fmt.Fprintf(buf, "May also implement %s, discovered by the host at runtime.\n", strings.Join(names, ", "))
+		}
+
+		if users := optionalFor[iface]; len(users) != 0 {
+			sort.Strings(users)
+			fmt.Fprintf(buf, "Optionally implemented by implementations of %s.\n", strings.Join(users, ", "))
+		}
+
+		if named, ok := iface.Typ.(*types.Named); ok && iface.Name != "" {
+			fmt.Fprintf(buf, "Declared as `%s`.\n", named)
+		}
+
+		for _, m := range iface.Methods {
+			gen.docsMethod(buf, iface, m, methodDiags[iface.Typ.String()+"."+m.Name], netrpc)
+		}
+	}
+
+	if len(otherDiags) != 0 {
+		fmt.Fprintf(buf, "\n## Caveats\n\n")
+		for _, d := range otherDiags {
+			fmt.Fprintf(buf, "- %s: %s\n", d.Severity, d.Message)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func (gen *Generator) docsMethod(buf *bytes.Buffer, iface *analyzer.Interface, m *analyzer.Method, diags []analyzer.Diagnostic, netrpc bool) {
+	fmt.Fprintf(buf, "\n#### %s\n\n", m.Name)
+	fmt.Fprintf(buf, "```go\n%s%s\n```\n\n", m.Name, gen.docsSignature(m))
+
+	if m.Doc != "" {
+		fmt.Fprintf(buf, "%s\n", m.Doc)
+	}
+
+	if len(m.Params) != 0 {
+		fmt.Fprintf(buf, "| Parameter | Type |\n|---|---|\n")
+		for i, p := range m.Params {
+			fmt.Fprintf(buf, "| %s | %s |\n", docsVarName(p, gen.paramName(m, i)), gen.docsType(p))
+		}
+		fmt.Fprintln(buf)
+	}
+
+	if len(m.Results) != 0 {
+		fmt.Fprintf(buf, "| Result | Type |\n|---|---|\n")
+		for i, r := range m.Results {
+			fmt.Fprintf(buf, "| %s | %s |\n", docsVarName(r, resultName(i)), gen.docsType(r))
+		}
+		fmt.Fprintln(buf)
+	}
+
+	var notes []string
+	if m.Skip {
+		notes = append(notes, "Not available over RPC; calls always fail.")
+	}
+	if m.Oneway {
+		notes = append(notes, "One-way: callers do not wait for the call to complete.")
+	}
+	if m.Idempotent && netrpc {
+		notes = append(notes, "Idempotent: may be retried after a connection failure.")
+	}
+	if m.Retain {
+		notes = append(notes, "Brokered parameters remain usable after the call returns.")
+	}
+	if timeout := m.Timeout; netrpc && (timeout != 0 || gen.opts.Timeout != 0) {
+		if timeout == 0 {
+			timeout = gen.opts.Timeout
+		}
+		notes = append(notes, fmt.Sprintf("Times out after %v.", timeout))
+	}
+	if !m.AllowError && hasError(m) {
+		notes = append(notes, "Errors are sent by message only; their types are not preserved.")
+	}
+	if !netrpc && returnsError(m) && len(m.Results) > 1 {
+		notes = append(notes, "When an error is returned, the other results are sent as its data.")
+	}
+	for _, d := range diags {
+		notes = append(notes, fmt.Sprintf("Caveat (%s): %s.", d.Severity, d.Message))
+	}
+
+	for _, n := range notes {
+		fmt.Fprintf(buf, "- %s\n", n)
+	}
+	if len(notes) != 0 {
+		fmt.Fprintln(buf)
+	}
+}
+
+func (gen *Generator) docsSignature(m *analyzer.Method) string {
+	qf := func(pkg *types.Package) string { return pkg.Name() }
+
+	var params, results []string
+	for i, p := range m.Params {
+		typ := types.TypeString(p.Typ, qf)
+		if m.Variadic && i == len(m.Params)-1 {
+			typ = "..." + types.TypeString(p.Typ.(*types.Slice).Elem(), qf)
+		}
+		params = append(params, strings.TrimSpace(p.Name+" "+typ))
+	}
+
+	for _, r := range m.Results {
+		results = append(results, strings.TrimSpace(r.Name+" "+types.TypeString(r.Typ, qf)))
+	}
+
+	sig := "(" + strings.Join(params, ", ") + ")"
+	switch {
+	case len(results) == 1 && m.Results[0].Name == "":
+		sig += " " + results[0]
+	case len(results) != 0:
+		sig += " (" + strings.Join(results, ", ") + ")"
+	}
+
+	return sig
+}
+
+func (gen *Generator) docsType(v *analyzer.Var) string {
+	typ := "`" + types.TypeString(v.Typ, func(pkg *types.Package) string { return pkg.Name() }) + "`"
+
+	if v.IFace != nil {
+		name, _ := gen.interfaceName(v.IFace)
+		return fmt.Sprintf("%s, brokered as [%s](#%s)", typ, name, strings.ToLower(name))
+	}
+
+	return typ
+}
+
+func docsVarName(v *analyzer.Var, fallback string) string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return fallback
+}
+
+func hasError(m *analyzer.Method) bool {
+	for _, v := range m.Params {
+		if typesext.IsError(v.Typ) {
+			return true
+		}
+	}
+	for _, v := range m.Results {
+		if typesext.IsError(v.Typ) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,14 +4,15 @@ import (
 	"bytes"
 	"fmt"
 	"go/types"
-	"hash"
 	"hash/fnv"
 	"io"
 	"log"
 	"sort"
+	"time"
 
 	"github.com/dave/jennifer/jen"
 	"github.com/jakebailey/plugingen/analyzer"
+	"github.com/jakebailey/plugingen/schema"
 	"github.com/jakebailey/plugingen/tojen"
 	"github.com/jakebailey/plugingen/typesext"
 )
@@ -22,49 +23,173 @@ const (
 
 	gopluginPath = "github.com/hashicorp/go-plugin"
 	netrpcPath   = "net/rpc"
+	runtimePath  = "github.com/jakebailey/plugingen/runtime"
+	jsonrpc2Path = runtimePath + "/jsonrpc2"
+
+	magicCookieKey = "PLUGINGEN_MAGIC_COOKIE_KEY"
 )
 
+// Options configures a Generator.
+type Options struct {
+	// RPCPanic makes generated clients exit on RPC call errors.
+	RPCPanic bool
+
+	// Timeout is the default timeout for methods without their own.
+	Timeout time.Duration
+
+	// TimeoutClose makes generated clients close their connection after a
+	// call times out.
+	TimeoutClose bool
+
+	// Supervisor enables generation of supervisor wrappers for top-level
+	// interfaces.
+	Supervisor bool
+
+	// Batch enables generation of batch clients, which send queued calls in
+	// a single RPC.
+	Batch bool
+
+	// Codec is the name of the runtime codec used for RPC connections. If
+	// empty, connections use gob as set up by go-plugin.
+	Codec string
+
+	// Unexported keeps the parameter and result types of methods unexported.
+	// Calls are then sent through a single Call method of each server, which
+	// encodes them itself.
+	Unexported bool
+
+	// PkgPath is the import path of the generated package. It qualifies the
+	// names batch types are registered with in gob, so that packages with
+	// the same name do not collide.
+	PkgPath string
+
+	// Naming holds the templates used to name generated identifiers. If
+	// nil, the templates in DefaultNaming are used.
+	Naming *Naming
+
+	// NewFile, if set, creates a file for the code of each interface, which
+	// is otherwise generated into the Generator's file with the shared code.
+	// The files are returned by Files.
+	NewFile func() *jen.File
+
+	// Logger, if set, is told about each interface as it is generated.
+	Logger *log.Logger
+}
+
 type Generator struct {
-	allowError bool
-	rpcPanic   bool
+	opts   Options
+	naming *Naming
 
 	file *jen.File
 
-	ifaceNames map[*analyzer.Interface]string
+	ifaceNames     map[*analyzer.Interface]string
+	ifaceNamesUsed map[string]bool
+	ifaceDeclared  map[string]bool
 
 	ifaceUnnamed      map[*types.Interface]string
 	ifaceUnnamedCount int
+	ifaceParents      map[*analyzer.Interface]ifaceParent
 
-	registerBasicError bool
+	paramNames map[*analyzer.Method][]string
+	fieldNames map[*analyzer.Method][]string
+
+	shared *jen.File
+	files  map[string]*jen.File
+
+	err error
 }
 
-func NewGenerator(allowError, rpcPanic bool, file *jen.File) *Generator {
+func NewGenerator(file *jen.File, opts Options) *Generator {
+	naming := opts.Naming
+	if naming == nil {
+		naming, _ = ParseNaming(nil)
+	}
+
 	return &Generator{
-		allowError:   allowError,
-		rpcPanic:     rpcPanic,
-		file:         file,
-		ifaceNames:   map[*analyzer.Interface]string{},
-		ifaceUnnamed: map[*types.Interface]string{},
+		opts:           opts,
+		naming:         naming,
+		file:           file,
+		ifaceNames:     map[*analyzer.Interface]string{},
+		ifaceNamesUsed: map[string]bool{},
+		ifaceDeclared:  map[string]bool{},
+		ifaceUnnamed:   map[*types.Interface]string{},
+		ifaceParents:   map[*analyzer.Interface]ifaceParent{},
+		paramNames:     map[*analyzer.Method][]string{},
+		fieldNames:     map[*analyzer.Method][]string{},
+		shared:         file,
+		files:          map[string]*jen.File{},
 	}
 }
 
+// Files returns the files created by Options.NewFile, keyed by the names of
+// the interfaces generated into them.
+func (gen *Generator) Files() map[string]*jen.File {
+	return gen.files
+}
+
+// Err returns the first error which occurred while generating code, such as
+// a naming template failing. The generated code should not be used if it is
+// not nil.
+func (gen *Generator) Err() error {
+	return gen.err
+}
+
+// startInterface switches to the file for the code of iface.
+func (gen *Generator) startInterface(iface *analyzer.Interface) {
+	if gen.opts.NewFile == nil {
+		return
+	}
+
+	name, _ := gen.interfaceName(iface)
+	gen.file = gen.opts.NewFile()
+	gen.files[name] = gen.file
+}
+
+// endInterfaces switches back to the file for shared code.
+func (gen *Generator) endInterfaces() {
+	gen.file = gen.shared
+}
+
 func (gen *Generator) Generate(ifaces []*analyzer.Interface) {
-	h := fnv.New128a()
-	imports := map[string]bool{}
-	buf := &bytes.Buffer{}
+	gen.nameInterfaces(ifaces)
+
+	if gen.opts.Codec != "" {
+		gen.file.Comment("pluginCodec is the name of the runtime codec used for RPC connections.")
+		gen.file.Const().Id("pluginCodec").Op("=").Lit(gen.opts.Codec)
+	}
 
 	for _, iface := range ifaces {
-		log.Println("generating plugin for", iface.Typ)
+		if gen.opts.Logger != nil {
+			gen.opts.Logger.Println("generating plugin for", iface.Typ)
+		}
+		gen.startInterface(iface)
 		gen.generateInterface(iface)
 		gen.generatePlugin(iface)
 		gen.generateRPC(iface)
 
-		qf := func(pkg *types.Package) string {
-			path := pkg.Path()
-			imports[path] = true
-			return path
+		if gen.opts.Supervisor && iface.TopLevel {
+			gen.generateSupervisor(iface)
 		}
+	}
+
+	gen.endInterfaces()
+	gen.generateHandshake(Handshake(ifaces))
+}
+
+// Handshake returns the magic cookie value for the generated handshake,
+// which is a hash of the interfaces' method sets.
+func Handshake(ifaces []*analyzer.Interface) string {
+	h := fnv.New128a()
+	imports := map[string]bool{}
+	buf := &bytes.Buffer{}
+
+	qf := func(pkg *types.Package) string {
+		path := pkg.Path()
+		imports[path] = true
+		return path
+	}
 
+	for _, iface := range ifaces {
 		buf.Reset()
 		types.WriteType(buf, iface.Typ.Underlying(), qf)
 
@@ -85,15 +210,55 @@ func (gen *Generator) Generate(ifaces []*analyzer.Interface) {
 		}
 	}
 
-	gen.generateHandshake(h)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// Schema describes ifaces for the given protocol, using the same names as
+// the generated code. For the "netrpc" protocol, the schema includes the
+// handshake.
+func (gen *Generator) Schema(protocol string, ifaces []*analyzer.Interface) *schema.Schema {
+	gen.nameInterfaces(ifaces)
 
-	if gen.registerBasicError {
-		gen.file.Func().Id("init").Params().BlockFunc(func(g *jen.Group) {
-			g.Qual("encoding/gob", "Register").Call(jen.Op("&").Qual(gopluginPath, "BasicError").Values())
-		})
+	// Interfaces analyzed only because they are embedded are served as part
+	// of the interfaces embedding them, so are not described on their own.
+	served := map[*analyzer.Interface]bool{}
+	for _, iface := range ifaces {
+		if iface.TopLevel {
+			served[iface] = true
+		}
+		for _, opt := range iface.Optional {
+			served[opt] = true
+		}
+		for _, m := range iface.Methods {
+			for _, p := range m.Params {
+				if p.IFace != nil {
+					served[p.IFace] = true
+				}
+			}
+		}
+	}
 
-		gen.registerBasicError = false
+	var described []*analyzer.Interface
+	for _, iface := range ifaces {
+		if served[iface] {
+			described = append(described, iface)
+		}
+	}
+
+	s := schema.Build(protocol, gen.opts.Codec, described, func(iface *analyzer.Interface) string {
+		name, _ := gen.interfaceName(iface)
+		return name
+	}, gen.paramNameEx)
+
+	if protocol == "netrpc" {
+		s.Handshake = &schema.Handshake{
+			ProtocolVersion:  1,
+			MagicCookieKey:   magicCookieKey,
+			MagicCookieValue: Handshake(ifaces),
+		}
 	}
+
+	return s
 }
 
 func (gen *Generator) generateInterface(iface *analyzer.Interface) {
@@ -101,10 +266,11 @@ func (gen *Generator) generateInterface(iface *analyzer.Interface) {
 		return
 	}
 
-	interfaceName, exists := gen.interfaceName(iface)
-	if exists {
+	interfaceName, _ := gen.interfaceName(iface)
+	if gen.ifaceDeclared[interfaceName] {
 		return
 	}
+	gen.ifaceDeclared[interfaceName] = true
 
 	typ := iface.Typ.Underlying()
 
@@ -165,10 +331,22 @@ func (gen *Generator) generatePlugin(iface *analyzer.Interface) {
 			jen.Interface(),
 			jen.Error(),
 		).
-		Block(jen.Return(
-			jen.Id("New"+clientName).Call(jen.Id("b"), jen.Id("c")),
-			jen.Nil(),
-		))
+		BlockFunc(func(g *jen.Group) {
+			if gen.opts.Codec != "" {
+				g.List(jen.Id("c"), jen.Id("err")).Op(":=").Qual(runtimePath, "Connect").Call(
+					jen.Id("b"),
+					jen.Id("c"),
+					jen.Id("pluginCodec"),
+				)
+				g.If(jen.Id("err").Op("!=").Nil()).Block(jen.Return(jen.Nil(), jen.Id("err")))
+				g.Line()
+			}
+
+			g.Return(
+				jen.Id("New"+clientName).Call(jen.Id("b"), jen.Id("c")),
+				jen.Nil(),
+			)
+		})
 }
 
 func (gen *Generator) generateRPC(iface *analyzer.Interface) {
@@ -176,46 +354,205 @@ func (gen *Generator) generateRPC(iface *analyzer.Interface) {
 
 	clientName := gen.clientName(iface)
 	gen.file.Commentf("%s implements %s via net/rpc.", clientName, interfaceName)
-	gen.file.Type().Id(clientName).Struct(
-		jen.Id("broker").Op("*").Qual(gopluginPath, "MuxBroker"),
-		jen.Id("client").Op("*").Qual(netrpcPath, "Client"),
-	)
+	if len(iface.Embeds) != 0 {
+		gen.file.Comment("Methods of embedded interfaces are implemented by the embedded clients.")
+	}
+	gen.file.Type().Id(clientName).StructFunc(func(g *jen.Group) {
+		g.Id("client").Op("*").Qual(runtimePath, "Client")
+		for _, e := range iface.Embeds {
+			g.Op("*").Id(gen.clientName(e))
+		}
+	})
+
+	newClient := jen.Qual(runtimePath, "NewClient").CallFunc(func(g *jen.Group) {
+		g.Lit(interfaceName)
+		g.Id("b")
+		g.Id("c")
+		g.Add(gen.errorHandler())
+
+		if gen.opts.TimeoutClose {
+			g.Qual(runtimePath, "CloseOnTimeout")
+		}
+
+		if gen.opts.Codec != "" {
+			g.Qual(runtimePath, "WithCodec").Call(jen.Id("pluginCodec"))
+		}
+
+		if gen.opts.Unexported {
+			g.Qual(runtimePath, "WithDispatch")
+		}
+	})
 
 	gen.file.Func().Id("New"+clientName).Params(
 		jen.Id("b").Op("*").Qual(gopluginPath, "MuxBroker"),
 		jen.Id("c").Op("*").Qual(netrpcPath, "Client"),
 	).Op("*").Id(clientName).
-		Block(jen.Return(jen.Op("&").Id(clientName).Values(jen.Dict{
-			jen.Id("broker"): jen.Id("b"),
-			jen.Id("client"): jen.Id("c"),
-		})))
+		BlockFunc(func(g *jen.Group) {
+			if len(iface.Embeds) == 0 {
+				g.Return(jen.Op("&").Id(clientName).Values(jen.Dict{
+					jen.Id("client"): newClient,
+				}))
+				return
+			}
+
+			// The embedded clients share the connection and its state.
+			g.Id("client").Op(":=").Add(newClient)
+			g.Return(gen.embeddedClient(iface))
+		})
 
 	gen.file.Var().Id("_").Add(tojen.Type(iface.Typ)).Op("=").
 		Parens(jen.Op("*").Id(clientName)).Parens(jen.Nil())
 
+	gen.file.Comment("SetRetryPolicy sets the policy used to retry calls to idempotent methods.")
+	gen.file.Func().
+		Params(jen.Id("c").Op("*").Id(clientName)).
+		Id("SetRetryPolicy").
+		Params(jen.Id("p").Op("*").Qual(runtimePath, "RetryPolicy")).
+		Block(jen.Id("c").Dot("client").Dot("SetRetryPolicy").Call(jen.Id("p")))
+
+	gen.file.Comment("Z_RuntimeClient returns the underlying runtime.Client.")
+	gen.file.Comment("It is exported for use by the runtime package and should not be used directly.")
+	gen.file.Func().
+		Params(jen.Id("c").Op("*").Id(clientName)).
+		Id("Z_RuntimeClient").
+		Params().
+		Op("*").Qual(runtimePath, "Client").
+		Block(jen.Return(jen.Id("c").Dot("client")))
+
 	serverName := gen.serverName(iface)
 	gen.file.Commentf("%s implements the net/rpc server for %s.", serverName, interfaceName)
-	gen.file.Type().Id(serverName).Struct(
-		jen.Id("broker").Op("*").Qual(gopluginPath, "MuxBroker"),
-		jen.Id("impl").Add(tojen.Type(iface.Typ)),
-	)
+	if len(iface.Embeds) != 0 {
+		gen.file.Comment("Methods of embedded interfaces are served by the embedded servers.")
+	}
+	gen.file.Type().Id(serverName).StructFunc(func(g *jen.Group) {
+		g.Id("broker").Op("*").Qual(gopluginPath, "MuxBroker")
+		g.Id("impl").Add(tojen.Type(iface.Typ))
+		for _, e := range iface.Embeds {
+			g.Op("*").Id(gen.serverName(e))
+		}
+	})
 
 	gen.file.Func().Id("New"+serverName).Params(
 		jen.Id("b").Op("*").Qual(gopluginPath, "MuxBroker"),
 		jen.Id("impl").Add(tojen.Type(iface.Typ)),
 	).Op("*").Id(serverName).
 		Block(jen.Return(
-			jen.Op("&").Id(serverName).Values(jen.Dict{
-				jen.Id("broker"): jen.Id("b"),
-				jen.Id("impl"):   jen.Id("impl"),
-			})))
+			jen.Op("&").Id(serverName).Values(jen.DictFunc(func(d jen.Dict) {
+				d[jen.Id("broker")] = jen.Id("b")
+				d[jen.Id("impl")] = jen.Id("impl")
+				for _, e := range iface.Embeds {
+					d[jen.Id(gen.serverName(e))] = jen.Id("New"+gen.serverName(e)).Call(jen.Id("b"), jen.Id("impl"))
+				}
+			}))))
+
+	if gen.opts.Codec != "" {
+		gen.file.Comment("Z_Connect serves s on a new connection using the named codec.")
+		gen.file.Comment("It is exported for compatibility with net/rpc and should not be used directly.")
+		gen.file.Func().
+			Params(jen.Id("s").Op("*").Id(serverName)).
+			Id("Z_Connect").
+			Params(
+				jen.Id("codec").String(),
+				jen.Id("id").Op("*").Uint32(),
+			).
+			Error().
+			Block(jen.Return(jen.Qual(runtimePath, "ServeConnect").Call(
+				jen.Id("s").Dot("broker"),
+				jen.Id("s"),
+				jen.Id("codec"),
+				jen.Id("id"),
+			)))
+	}
 
 	for _, m := range iface.Methods {
+		if methodOwner(iface, m) != iface {
+			continue
+		}
 		gen.generateRPCMethod(iface, m)
 	}
+
+	if gen.opts.Unexported {
+		gen.generateDispatch(iface)
+	}
+
+	gen.generateCapabilities(iface)
+
+	if gen.opts.Batch {
+		gen.generateBatch(iface)
+	}
+}
+
+// embeddedClient returns a client for iface using the runtime.Client named
+// client, including the clients of the interfaces it embeds.
+func (gen *Generator) embeddedClient(iface *analyzer.Interface) *jen.Statement {
+	clientName := gen.clientName(iface)
+
+	return jen.Op("&").Id(clientName).Values(jen.DictFunc(func(d jen.Dict) {
+		d[jen.Id("client")] = jen.Id("client")
+		for _, e := range iface.Embeds {
+			d[jen.Id(gen.clientName(e))] = gen.embeddedClient(e)
+		}
+	}))
+}
+
+func (gen *Generator) generateSupervisor(iface *analyzer.Interface) {
+	interfaceName, _ := gen.interfaceName(iface)
+	clientName := gen.clientName(iface)
+	supervisorName := gen.supervisorName(iface)
+
+	gen.file.Commentf("%s implements %s using a plugin process managed by a runtime.Supervisor,", supervisorName, interfaceName)
+	gen.file.Comment("which is restarted if the connection to it is lost.")
+	gen.file.Type().Id(supervisorName).Struct(
+		jen.Op("*").Id(clientName),
+		jen.Id("Supervisor").Op("*").Qual(runtimePath, "Supervisor"),
+	)
+
+	gen.file.Commentf("New%s starts a plugin process configured by factory and dispenses the", supervisorName)
+	gen.file.Comment("named plugin from it. Failed calls are retried according to policy, after")
+	gen.file.Comment("restarting the plugin process if needed.")
+	gen.file.Func().Id("New"+supervisorName).Params(
+		jen.Id("name").String(),
+		jen.Id("factory").Func().Params().Op("*").Qual(gopluginPath, "ClientConfig"),
+		jen.Id("policy").Qual(runtimePath, "RetryPolicy"),
+	).Params(
+		jen.Op("*").Id(supervisorName),
+		jen.Error(),
+	).Block(
+		jen.Id("supervisor").Op(":=").Qual(runtimePath, "NewSupervisor").Call(jen.Id("name"), jen.Id("factory")),
+		jen.Line(),
+		jen.List(jen.Id("raw"), jen.Id("err")).Op(":=").Id("supervisor").Dot("Start").Call(jen.Id("policy")),
+		jen.If(jen.Id("err").Op("!=").Nil()).Block(
+			jen.Return(jen.Nil(), jen.Id("err")),
+		),
+		jen.Line(),
+		jen.List(jen.Id("client"), jen.Id("ok")).Op(":=").Id("raw").Assert(jen.Op("*").Id(clientName)),
+		jen.If(jen.Op("!").Id("ok")).Block(
+			jen.Id("supervisor").Dot("Kill").Call(),
+			jen.Return(
+				jen.Nil(),
+				jen.Qual("fmt", "Errorf").Call(jen.Lit("plugin %q dispensed %T, not *"+clientName), jen.Id("name"), jen.Id("raw")),
+			),
+		),
+		jen.Line(),
+		jen.Return(
+			jen.Op("&").Id(supervisorName).Values(jen.Dict{
+				jen.Id(clientName):   jen.Id("client"),
+				jen.Id("Supervisor"): jen.Id("supervisor"),
+			}),
+			jen.Nil(),
+		),
+	)
+
+	gen.file.Var().Id("_").Add(tojen.Type(iface.Typ)).Op("=").
+		Parens(jen.Op("*").Id(supervisorName)).Parens(jen.Nil())
 }
 
 func (gen *Generator) generateRPCMethod(iface *analyzer.Interface, m *analyzer.Method) {
+	if m.Skip {
+		gen.generateSkippedClientMethod(iface, m, gen.clientName(iface))
+		return
+	}
+
 	gen.generateRPCMethodStructs(iface, m)
 	gen.generateRPCClientMethod(iface, m)
 	gen.generateRPCServerMethod(iface, m)
@@ -227,150 +564,236 @@ func (gen *Generator) generateRPCMethodStructs(iface *analyzer.Interface, m *ana
 
 	if len(m.Params) != 0 {
 		gen.file.Commentf("%s contains parameters for the %s function.", paramsStructName, m.Name)
-		gen.file.Comment("It is exported for compatibility with net/rpc and should not be used directly.")
+		if !gen.opts.Unexported {
+			gen.file.Comment("It is exported for compatibility with net/rpc and should not be used directly.")
+		}
 		gen.file.Type().Id(paramsStructName).StructFunc(func(g *jen.Group) {
 			for i, param := range m.Params {
 				if param.IFace != nil {
-					g.Id(paramNameEx(i) + "ID").Uint32()
+					g.Id(gen.paramNameEx(m, i) + "ID").Uint32()
 					continue
 				}
 
-				g.Id(paramNameEx(i)).Add(tojen.Type(param.Typ))
+				g.Id(gen.paramNameEx(m, i)).Add(gen.fieldType(param.Typ))
 			}
 		})
 	}
 
 	if len(m.Results) != 0 {
 		gen.file.Commentf("%s contains results for the %s function.", resultsStructName, m.Name)
-		gen.file.Comment("It is exported for compatibility with net/rpc and should not be used directly.")
+		if !gen.opts.Unexported {
+			gen.file.Comment("It is exported for compatibility with net/rpc and should not be used directly.")
+		}
 		gen.file.Type().Id(resultsStructName).StructFunc(func(g *jen.Group) {
 			for i, result := range m.Results {
-				g.Id(resultNameEx(i)).Add(tojen.Type(result.Typ))
+				g.Id(resultNameEx(i)).Add(gen.fieldType(result.Typ))
 			}
 		})
 	}
 }
 
-func (gen *Generator) generateRPCClientMethod(iface *analyzer.Interface, m *analyzer.Method) {
+func (gen *Generator) generateSkippedClientMethod(iface *analyzer.Interface, m *analyzer.Method, clientName string) {
 	interfaceName, _ := gen.interfaceName(iface)
-	clientName := gen.clientName(iface)
-	paramsStructName := gen.paramsStructName(iface, m)
-	resultsStructName := gen.resultsStructName(iface, m)
 
 	gen.file.Commentf("%s implements %s for the %s interface.", m.Name, m.Name, interfaceName)
+	gen.file.Comment("It is not available over RPC, and always fails.")
 	gen.file.Func().
 		Params(jen.Id("c").Op("*").Id(clientName)).
 		Id(m.Name).
-		ParamsFunc(func(g *jen.Group) {
-			for i, param := range m.Params {
-				if m.Variadic && i == len(m.Params)-1 {
-					sl := param.Typ.(*types.Slice)
-					g.Id(paramName(i)).Op("...").Add(tojen.Type(sl.Elem()))
-				} else {
-					g.Id(paramName(i)).Add(tojen.Type(param.Typ))
+		Add(gen.clientMethodSignature(m)).
+		BlockFunc(func(g *jen.Group) {
+			call := jen.Id("c").Dot("client").Dot("Unavailable").Call(jen.Lit(m.Name))
+
+			if len(m.Results) == 0 {
+				g.Add(call)
+				return
+			}
+
+			last := len(m.Results) - 1
+			if !typesext.IsError(m.Results[last].Typ) {
+				g.Add(call)
+				g.Line()
+				last = -1
+			}
+
+			g.ReturnFunc(func(g *jen.Group) {
+				for i, result := range m.Results {
+					if i == last {
+						g.Add(call)
+						continue
+					}
+					g.Op("*").New(tojen.Type(result.Typ))
 				}
+			})
+		})
+}
+
+func (gen *Generator) clientMethodParams(m *analyzer.Method) *jen.Statement {
+	return jen.ParamsFunc(func(g *jen.Group) {
+		for i, param := range m.Params {
+			if m.Variadic && i == len(m.Params)-1 {
+				sl := param.Typ.(*types.Slice)
+				g.Id(gen.paramName(m, i)).Op("...").Add(tojen.Type(sl.Elem()))
+			} else {
+				g.Id(gen.paramName(m, i)).Add(tojen.Type(param.Typ))
 			}
-		}).
+		}
+	})
+}
+
+func (gen *Generator) clientMethodSignature(m *analyzer.Method) *jen.Statement {
+	return gen.clientMethodParams(m).
 		ParamsFunc(func(g *jen.Group) {
 			for _, result := range m.Results {
 				g.Add(tojen.Type(result.Typ))
 			}
-		}).
-		BlockFunc(func(g *jen.Group) {
-			for i, param := range m.Params {
-				if param.IFace == nil {
-					continue
-				}
+		})
+}
 
-				idName := paramName(i) + "id"
-				g.Id(idName).Op(":=").
-					Id("c").Dot("broker").Dot("NextId").Call()
+func (gen *Generator) generateRPCClientMethod(iface *analyzer.Interface, m *analyzer.Method) {
+	interfaceName, _ := gen.interfaceName(iface)
+	clientName := gen.clientName(iface)
+	paramsStructName := gen.paramsStructName(iface, m)
+	resultsStructName := gen.resultsStructName(iface, m)
 
-				paramServerName := gen.serverName(param.IFace)
+	gen.file.Commentf("%s implements %s for the %s interface.", m.Name, m.Name, interfaceName)
+	if m.Oneway {
+		gen.file.Comment("It does not wait for the call to complete.")
+	}
+	gen.file.Func().
+		Params(jen.Id("c").Op("*").Id(clientName)).
+		Id(m.Name).
+		Add(gen.clientMethodSignature(m)).
+		BlockFunc(func(g *jen.Group) {
+			params := jen.Nil()
+			if len(m.Params) != 0 {
+				g.Id(paramsStructID).Op(":=").
+					Op("&").Id(paramsStructName).
+					Add(gen.clientParamsValues(m))
+				params = jen.Id(paramsStructID)
+			}
 
-				g.Go().Id("c").Dot("broker").Dot("AcceptAndServe").Call(
-					jen.Id(idName),
-					jen.Id("New"+paramServerName).Call(
-						jen.Id("c").Dot("broker"),
-						jen.Id(paramName(i)),
-					),
-				)
+			results := jen.Nil()
+			if len(m.Results) != 0 {
+				g.Id(resultsStructID).Op(":=").Op("&").Id(resultsStructName).Values()
+				results = jen.Id(resultsStructID)
+			}
 
+			if len(m.Params) != 0 || len(m.Results) != 0 {
 				g.Line()
 			}
 
-			if len(m.Params) == 0 {
-				g.Id(paramsStructID).Op(":=").New(jen.Interface())
-			} else {
-				g.Id(paramsStructID).Op(":=").
-					Op("&").Id(paramsStructName).
-					Values(jen.DictFunc(func(d jen.Dict) {
-						for i, param := range m.Params {
-							if !gen.allowError && typesext.IsError(param.Typ) {
-								d[jen.Id(paramNameEx(i))] = jen.Qual(gopluginPath, "NewBasicError").
-									Call(jen.Id(paramName(i)))
-								continue
-							}
-
-							if param.IFace != nil {
-								d[jen.Id(paramNameEx(i)+"ID")] = jen.Id(paramName(i) + "id")
-								continue
-							}
-
-							d[jen.Id(paramNameEx(i))] = jen.Id(paramName(i))
-						}
-					}))
+			if m.Oneway {
+				g.Id("c").Dot("client").Dot("Go").Call(jen.Lit(m.Name), params)
+				return
 			}
 
-			if len(m.Results) == 0 {
-				g.Id(resultsStructID).Op(":=").New(jen.Interface())
-			} else {
-				g.Id(resultsStructID).Op(":=").Op("&").Id(resultsStructName).Values()
+			timeout := m.Timeout
+			if timeout == 0 {
+				timeout = gen.opts.Timeout
 			}
 
-			g.Line()
+			brokered := false
+			for _, param := range m.Params {
+				if param.IFace != nil {
+					brokered = true
+				}
+			}
 
-			var errFunc string
-			if gen.rpcPanic {
-				errFunc = "Fatalln"
+			if timeout == 0 && !m.Idempotent && !brokered {
+				g.Id("c").Dot("client").Dot("Call").Call(jen.Lit(m.Name), params, results)
 			} else {
-				errFunc = "Println"
-			}
+				returnsError := timeout != 0 && len(m.Results) != 0 && typesext.IsError(m.Results[len(m.Results)-1].Typ)
+
+				call := jen.Id("c").Dot("client").Dot("CallWith").Call(
+					jen.Lit(m.Name),
+					params,
+					results,
+					jen.Qual(runtimePath, "CallOptions").Values(jen.DictFunc(func(d jen.Dict) {
+						if timeout != 0 {
+							d[jen.Id("Timeout")] = durationToJen(timeout)
+							if returnsError {
+								d[jen.Id("ReturnsError")] = jen.True()
+							}
+						}
+						if m.Idempotent {
+							d[jen.Id("Idempotent")] = jen.True()
+						}
+						if brokered {
+							d[jen.Id("Brokered")] = jen.True()
+						}
+					})),
+				)
 
-			g.If(
-				jen.Id("err").Op(":=").Id("c").Dot("client").Dot("Call").Call(
-					jen.Lit("Plugin."+m.Name),
-					jen.Id(paramsStructID),
-					jen.Id(resultsStructID),
-				),
-				jen.Id("err").Op("!=").Nil(),
-			).Block(
-				jen.Qual("log", errFunc).Call(
-					jen.Lit(fmt.Sprintf("RPC call to %s.%s failed:", interfaceName, m.Name)),
-					jen.Id("err").Dot("Error").Call(),
-				),
-			)
+				if returnsError {
+					g.If(jen.Id("err").Op(":=").Add(call), jen.Id("err").Op("!=").Nil()).Block(
+						jen.ReturnFunc(func(g *jen.Group) {
+							for i, result := range m.Results[:len(m.Results)-1] {
+								g.Add(gen.unwrapValue(result.Typ, jen.Id(resultsStructID).Dot(resultNameEx(i))))
+							}
+							g.Id("err")
+						}),
+					)
+				} else {
+					g.Add(call)
+				}
+			}
 
 			if len(m.Results) != 0 {
 				g.Line()
 				g.ReturnFunc(func(g *jen.Group) {
-					for i := range m.Results {
-						g.Id(resultsStructID).Dot(resultNameEx(i))
+					for i, result := range m.Results {
+						g.Add(gen.unwrapValue(result.Typ, jen.Id(resultsStructID).Dot(resultNameEx(i))))
 					}
 				})
 			}
 		})
 }
 
+// clientParamsValues returns the values of a params struct, built from the
+// parameters of a client method with receiver c.
+func (gen *Generator) clientParamsValues(m *analyzer.Method) *jen.Statement {
+	return jen.Values(jen.DictFunc(func(d jen.Dict) {
+		for i, param := range m.Params {
+			if param.IFace != nil {
+				paramServerName := gen.serverName(param.IFace)
+
+				d[jen.Id(gen.paramNameEx(m, i)+"ID")] = jen.Id("c").Dot("client").Dot("Serve").Call(
+					jen.Id("New"+paramServerName).Call(
+						jen.Id("c").Dot("client").Dot("Broker").Call(),
+						jen.Id(gen.paramName(m, i)),
+					),
+				)
+				continue
+			}
+
+			d[jen.Id(gen.paramNameEx(m, i))] = gen.wrapValue(m, param.Typ, jen.Id(gen.paramName(m, i)))
+		}
+	}))
+}
+
+// emptyResults returns the type of the results of server methods whose
+// method has none. net/rpc/jsonrpc treats a null result as an error, so an
+// empty struct is sent instead when a codec is used.
+func (gen *Generator) emptyResults() *jen.Statement {
+	if gen.opts.Codec != "" {
+		return jen.Op("*").Struct()
+	}
+	return jen.Op("*").Interface()
+}
+
 func (gen *Generator) generateRPCServerMethod(iface *analyzer.Interface, m *analyzer.Method) {
+	interfaceName, _ := gen.interfaceName(iface)
 	serverName := gen.serverName(iface)
 	paramsStructName := gen.paramsStructName(iface, m)
 	resultsStructName := gen.resultsStructName(iface, m)
 
-	gen.file.Commentf("%s implements the server side of net/rpc calls to %s.", m.Name, m.Name)
+	serverMethodName := gen.serverMethodName(m)
+
+	gen.file.Commentf("%s implements the server side of net/rpc calls to %s.", serverMethodName, m.Name)
 	gen.file.Func().
 		Params(jen.Id("s").Op("*").Id(serverName)).
-		Id(m.Name).
+		Id(serverMethodName).
 		ParamsFunc(func(g *jen.Group) {
 			if len(m.Params) == 0 {
 				g.Id("_").Interface()
@@ -379,31 +802,48 @@ func (gen *Generator) generateRPCServerMethod(iface *analyzer.Interface, m *anal
 			}
 
 			if len(m.Results) == 0 {
-				g.Id("_").Op("*").Interface()
+				g.Id("_").Add(gen.emptyResults())
 			} else {
 				g.Id(resultsStructID).Op("*").Id(resultsStructName)
 			}
 		}).
-		Params(jen.Error()).
+		Params(jen.Id("err").Error()).
 		BlockFunc(func(g *jen.Group) {
+			g.Defer().Qual(runtimePath, "Recover").Call(
+				jen.Lit(interfaceName+"."+m.Name),
+				jen.Op("&").Id("err"),
+			)
+			g.Line()
+
 			for i, param := range m.Params {
 				if param.IFace == nil {
 					continue
 				}
 
 				paramClientName := gen.clientName(param.IFace)
-				idName := paramNameEx(i) + "ID"
-				connName := paramName(i) + "conn"
-				rpcName := paramName(i) + "RPCClient"
-				clientName := paramName(i) + "client"
-
-				g.List(jen.Id(connName), jen.Id("err")).Op(":=").
-					Id("s").Dot("broker").Dot("Dial").Call(jen.Id("params").Dot(idName))
-
+				idName := gen.paramNameEx(m, i) + "ID"
+				rpcName := gen.paramName(m, i) + "rpc"
+				clientName := gen.paramName(m, i) + "client"
+
+				if gen.opts.Codec != "" {
+					g.List(jen.Id(rpcName), jen.Id("err")).Op(":=").
+						Qual(runtimePath, "DialCodec").Call(
+						jen.Id("s").Dot("broker"),
+						jen.Id(paramsStructID).Dot(idName),
+						jen.Id("pluginCodec"),
+					)
+				} else {
+					g.List(jen.Id(rpcName), jen.Id("err")).Op(":=").
+						Qual(runtimePath, "Dial").Call(
+						jen.Id("s").Dot("broker"),
+						jen.Id(paramsStructID).Dot(idName),
+					)
+				}
 				g.If(jen.Id("err").Op("!=").Nil()).Block(jen.Return(jen.Id("err")))
 
-				g.Id(rpcName).Op(":=").Qual(netrpcPath, "NewClient").Call(jen.Id(connName))
-				g.Defer().Id(rpcName).Dot("Close").Call()
+				if !m.Retain {
+					g.Defer().Id(rpcName).Dot("Close").Call()
+				}
 
 				g.Id(clientName).Op(":=").Id("New"+paramClientName).Call(
 					jen.Id("s").Dot("broker"),
@@ -429,34 +869,26 @@ func (gen *Generator) generateRPCServerMethod(iface *analyzer.Interface, m *anal
 				ParamsFunc(func(g *jen.Group) {
 					for i, param := range m.Params {
 						if m.Variadic && i == len(m.Params)-1 {
-							g.Id(paramsStructID).Dot(paramNameEx(i)).Op("...")
+							g.Id(paramsStructID).Dot(gen.paramNameEx(m, i)).Op("...")
 							continue
 						}
 
 						if param.IFace != nil {
-							g.Id(paramName(i) + "client")
+							g.Id(gen.paramName(m, i) + "client")
 							continue
 						}
 
-						g.Id(paramsStructID).Dot(paramNameEx(i))
+						g.Add(gen.unwrapValue(param.Typ, jen.Id(paramsStructID).Dot(gen.paramNameEx(m, i))))
 					}
 				})
 
-			g.Line()
+			if len(m.Results) != 0 {
+				g.Line()
+			}
 
 			for i, result := range m.Results {
-				if !gen.allowError && typesext.IsError(result.Typ) {
-					g.If(jen.Id(resultName(i)).Op("==").Nil()).BlockFunc(func(g *jen.Group) {
-						g.Id(resultsStructID).Dot(resultNameEx(i)).Op("=").Nil()
-					}).Else().BlockFunc(func(g *jen.Group) {
-						g.Id(resultsStructID).Dot(resultNameEx(i)).Op("=").
-							Qual(gopluginPath, "NewBasicError").Call(jen.Id(resultName(i)))
-					})
-					gen.registerBasicError = true
-					continue
-				}
-
-				g.Id(resultsStructID).Dot(resultNameEx(i)).Op("=").Id(resultName(i))
+				g.Id(resultsStructID).Dot(resultNameEx(i)).Op("=").
+					Add(gen.wrapValue(m, result.Typ, jen.Id(resultName(i))))
 			}
 
 			g.Line()
@@ -464,14 +896,77 @@ func (gen *Generator) generateRPCServerMethod(iface *analyzer.Interface, m *anal
 		})
 }
 
-func (gen *Generator) generateHandshake(h hash.Hash) {
-	sum := fmt.Sprintf("%x", h.Sum(nil))
-
+func (gen *Generator) generateHandshake(sum string) {
 	gen.file.Comment("PluginHandshake is a plugin handshake generated from the input interfaces.")
 	gen.file.Var().Id("PluginHandshake").Op("=").
 		Qual(gopluginPath, "HandshakeConfig").Values(jen.Dict{
 		jen.Id("ProtocolVersion"):  jen.Lit(1),
-		jen.Id("MagicCookieKey"):   jen.Lit("PLUGINGEN_MAGIC_COOKIE_KEY"),
+		jen.Id("MagicCookieKey"):   jen.Lit(magicCookieKey),
 		jen.Id("MagicCookieValue"): jen.Lit(sum),
 	})
 }
+
+// errorHandler returns the runtime.ErrorHandler used by generated clients.
+func (gen *Generator) errorHandler() *jen.Statement {
+	if gen.opts.RPCPanic {
+		return jen.Qual(runtimePath, "FatalError")
+	}
+	return jen.Qual(runtimePath, "LogError")
+}
+
+// fieldType returns the type of a params or results struct field holding
+// values of typ. Codecs other than gob cannot encode interface values, so
+// errors are sent as *plugin.BasicError.
+func (gen *Generator) fieldType(typ types.Type) *jen.Statement {
+	if gen.opts.Codec != "" && typesext.IsError(typ) {
+		return jen.Op("*").Qual(gopluginPath, "BasicError")
+	}
+	return tojen.Type(typ)
+}
+
+// wrapValue converts v, of type typ, for storage in a params or results
+// struct field, wrapping errors so that they can be encoded.
+func (gen *Generator) wrapValue(m *analyzer.Method, typ types.Type, v *jen.Statement) *jen.Statement {
+	if !typesext.IsError(typ) {
+		return v
+	}
+
+	if gen.opts.Codec != "" {
+		return jen.Qual(runtimePath, "ToBasicError").Call(v)
+	}
+
+	if !m.AllowError {
+		return jen.Qual(runtimePath, "WrapError").Call(v)
+	}
+
+	return v
+}
+
+// unwrapValue converts v, a params or results struct field, back to typ.
+func (gen *Generator) unwrapValue(typ types.Type, v *jen.Statement) *jen.Statement {
+	if gen.opts.Codec != "" && typesext.IsError(typ) {
+		return jen.Qual(runtimePath, "FromBasicError").Call(v)
+	}
+	return v
+}
+
+func durationToJen(d time.Duration) *jen.Statement {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "Hour"},
+		{time.Minute, "Minute"},
+		{time.Second, "Second"},
+		{time.Millisecond, "Millisecond"},
+		{time.Microsecond, "Microsecond"},
+	}
+
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return jen.Lit(int(d/u.unit)).Op("*").Qual("time", u.name)
+		}
+	}
+
+	return jen.Qual("time", "Duration").Call(jen.Lit(int64(d)))
+}
@@ -0,0 +1,283 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/jakebailey/plugingen/analyzer"
+	"github.com/jakebailey/plugingen/schema"
+	"github.com/jakebailey/plugingen/tojen"
+	"github.com/jakebailey/plugingen/typesext"
+)
+
+// GenerateJSONRPC generates JSON-RPC 2.0 clients and handlers for ifaces,
+// for use with plugins written in other languages, and returns the schema
+// describing them.
+func (gen *Generator) GenerateJSONRPC(ifaces []*analyzer.Interface) *schema.Schema {
+	gen.nameInterfaces(ifaces)
+
+	for _, iface := range ifaces {
+		gen.startInterface(iface)
+		gen.generateInterface(iface)
+		gen.generateJSONClient(iface)
+		gen.generateJSONHandler(iface)
+	}
+	gen.endInterfaces()
+
+	return gen.Schema("jsonrpc2", ifaces)
+}
+
+// returnsError reports whether the last result of m is an error, which is
+// sent as a JSON-RPC error rather than as a result, with the other results
+// as its data.
+func returnsError(m *analyzer.Method) bool {
+	return len(m.Results) != 0 && typesext.IsError(m.Results[len(m.Results)-1].Typ)
+}
+
+// jsonType returns the type used to encode values of v's type.
+func jsonType(v *analyzer.Var) *jen.Statement {
+	if v.IFace != nil {
+		return jen.Uint64()
+	}
+
+	if typesext.IsError(v.Typ) {
+		return jen.Op("*").Qual(jsonrpc2Path, "Error")
+	}
+
+	return tojen.Type(v.Typ)
+}
+
+func (gen *Generator) generateJSONClient(iface *analyzer.Interface) {
+	interfaceName, _ := gen.interfaceName(iface)
+	clientName := gen.jsonClientName(iface)
+
+	gen.file.Commentf("%s implements %s via JSON-RPC 2.0.", clientName, interfaceName)
+	gen.file.Type().Id(clientName).Struct(
+		jen.Id("client").Op("*").Qual(jsonrpc2Path, "Client"),
+	)
+
+	gen.file.Commentf("New%s returns a client which calls the object with the given ID.", clientName)
+	gen.file.Comment("The plugin's root object has ID 0.")
+	gen.file.Func().Id("New"+clientName).Params(
+		jen.Id("conn").Op("*").Qual(jsonrpc2Path, "Conn"),
+		jen.Id("object").Uint64(),
+	).Op("*").Id(clientName).
+		Block(jen.Return(jen.Op("&").Id(clientName).Values(jen.Dict{
+			jen.Id("client"): jen.Qual(jsonrpc2Path, "NewClient").Call(
+				jen.Lit(interfaceName),
+				jen.Id("conn"),
+				jen.Id("object"),
+				gen.errorHandler(),
+			),
+		})))
+
+	gen.file.Var().Id("_").Add(tojen.Type(iface.Typ)).Op("=").
+		Parens(jen.Op("*").Id(clientName)).Parens(jen.Nil())
+
+	for _, m := range iface.Methods {
+		if m.Skip {
+			gen.generateSkippedClientMethod(iface, m, clientName)
+			continue
+		}
+
+		gen.generateJSONClientMethod(iface, m)
+	}
+}
+
+func (gen *Generator) generateJSONClientMethod(iface *analyzer.Interface, m *analyzer.Method) {
+	interfaceName, _ := gen.interfaceName(iface)
+	clientName := gen.jsonClientName(iface)
+
+	results := m.Results
+	if returnsError(m) {
+		results = results[:len(results)-1]
+	}
+
+	gen.file.Commentf("%s implements %s for the %s interface.", m.Name, m.Name, interfaceName)
+	if m.Oneway {
+		gen.file.Comment("It does not wait for the call to complete.")
+	}
+	gen.file.Func().
+		Params(jen.Id("c").Op("*").Id(clientName)).
+		Id(m.Name).
+		Add(gen.clientMethodSignature(m)).
+		BlockFunc(func(g *jen.Group) {
+			registered := false
+			for i, param := range m.Params {
+				if param.IFace == nil {
+					continue
+				}
+				registered = true
+
+				idName := gen.paramName(m, i) + "id"
+				g.Id(idName).Op(":=").Id("c").Dot("client").Dot("Conn").Call().Dot("Register").Call(
+					jen.Id("New"+gen.jsonHandlerName(param.IFace)).Call(
+						jen.Id("c").Dot("client").Dot("Conn").Call(),
+						jen.Id(gen.paramName(m, i)),
+					),
+				)
+
+				if !m.Retain {
+					g.Defer().Id("c").Dot("client").Dot("Conn").Call().Dot("Release").Call(jen.Id(idName))
+				}
+			}
+
+			if len(results) != 0 {
+				g.Var().DefsFunc(func(g *jen.Group) {
+					for i, result := range results {
+						g.Id(resultName(i)).Add(jsonType(result))
+					}
+				})
+			}
+
+			if len(results) != 0 || registered {
+				g.Line()
+			}
+
+			params := jen.Index().Interface().ValuesFunc(func(g *jen.Group) {
+				for i, param := range m.Params {
+					switch {
+					case param.IFace != nil:
+						g.Id(gen.paramName(m, i) + "id")
+					case typesext.IsError(param.Typ):
+						g.Qual(jsonrpc2Path, "NewError").Call(jen.Id(gen.paramName(m, i)))
+					default:
+						g.Id(gen.paramName(m, i))
+					}
+				}
+			})
+
+			args := []jen.Code{jen.Lit(m.Name), params}
+			for i := range results {
+				args = append(args, jen.Op("&").Id(resultName(i)))
+			}
+
+			if m.Oneway {
+				g.Id("c").Dot("client").Dot("Notify").Call(args...)
+				return
+			}
+
+			if returnsError(m) {
+				g.Id("err").Op(":=").Id("c").Dot("client").Dot("CallError").Call(args...)
+			} else {
+				g.Id("c").Dot("client").Dot("Call").Call(args...)
+			}
+
+			if len(m.Results) == 0 {
+				return
+			}
+
+			g.Line()
+			g.ReturnFunc(func(g *jen.Group) {
+				for i, result := range results {
+					if typesext.IsError(result.Typ) {
+						g.Id(resultName(i)).Dot("Err").Call()
+						continue
+					}
+					g.Id(resultName(i))
+				}
+
+				if returnsError(m) {
+					g.Id("err")
+				}
+			})
+		})
+}
+
+func (gen *Generator) generateJSONHandler(iface *analyzer.Interface) {
+	interfaceName, _ := gen.interfaceName(iface)
+	handlerName := gen.jsonHandlerName(iface)
+
+	gen.file.Commentf("New%s returns a handler which calls impl, to be passed to", handlerName)
+	gen.file.Comment("Serve or Register on conn.")
+	gen.file.Func().Id("New"+handlerName).Params(
+		jen.Id("conn").Op("*").Qual(jsonrpc2Path, "Conn"),
+		jen.Id("impl").Add(tojen.Type(iface.Typ)),
+	).Qual(jsonrpc2Path, "Handler").
+		Block(jen.Return(jen.Func().
+			Params(
+				jen.Id("method").String(),
+				jen.Id("params").Index().Qual("encoding/json", "RawMessage"),
+			).
+			Params(jen.Index().Interface(), jen.Error()).
+			Block(
+				jen.Switch(jen.Id("method")).BlockFunc(func(g *jen.Group) {
+					for _, m := range iface.Methods {
+						if m.Skip {
+							continue
+						}
+
+						g.Case(jen.Lit(m.Name)).BlockFunc(func(g *jen.Group) {
+							gen.generateJSONHandlerCase(g, m)
+						})
+					}
+				}),
+				jen.Return(jen.Nil(), jen.Qual(jsonrpc2Path, "MethodNotFound").Call(jen.Lit(interfaceName), jen.Id("method"))),
+			)))
+}
+
+func (gen *Generator) generateJSONHandlerCase(g *jen.Group, m *analyzer.Method) {
+	if len(m.Params) != 0 {
+		g.Var().DefsFunc(func(g *jen.Group) {
+			for i, param := range m.Params {
+				g.Id(gen.paramName(m, i)).Add(jsonType(param))
+			}
+		})
+	}
+
+	g.If(
+		jen.Id("err").Op(":=").Qual(jsonrpc2Path, "UnmarshalParams").CallFunc(func(g *jen.Group) {
+			g.Id("params")
+			for i := range m.Params {
+				g.Op("&").Id(gen.paramName(m, i))
+			}
+		}),
+		jen.Id("err").Op("!=").Nil(),
+	).Block(jen.Return(jen.Nil(), jen.Id("err")))
+	g.Line()
+
+	line := g.Null()
+	if len(m.Results) != 0 {
+		line = g.ListFunc(func(g *jen.Group) {
+			for i := range m.Results {
+				g.Id(resultName(i))
+			}
+		}).Op(":=")
+	}
+
+	line.Id("impl").Dot(m.Name).CallFunc(func(g *jen.Group) {
+		for i, param := range m.Params {
+			switch {
+			case param.IFace != nil:
+				g.Id("New"+gen.jsonClientName(param.IFace)).Call(jen.Id("conn"), jen.Id(gen.paramName(m, i)))
+			case typesext.IsError(param.Typ):
+				g.Id(gen.paramName(m, i)).Dot("Err").Call()
+			case m.Variadic && i == len(m.Params)-1:
+				g.Id(gen.paramName(m, i)).Op("...")
+			default:
+				g.Id(gen.paramName(m, i))
+			}
+		}
+	})
+
+	// A trailing error is returned with the other results, which are sent
+	// as its data.
+	results := m.Results
+	err := jen.Nil()
+	if returnsError(m) {
+		err = jen.Id(resultName(len(results) - 1))
+		results = results[:len(results)-1]
+	}
+
+	g.Line()
+	g.Return(
+		jen.Index().Interface().ValuesFunc(func(g *jen.Group) {
+			for i, result := range results {
+				if typesext.IsError(result.Typ) {
+					g.Qual(jsonrpc2Path, "NewError").Call(jen.Id(resultName(i)))
+					continue
+				}
+				g.Id(resultName(i))
+			}
+		}),
+		err,
+	)
+}
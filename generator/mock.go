@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/jakebailey/plugingen/analyzer"
+	"github.com/jakebailey/plugingen/tojen"
+)
+
+// GenerateMocks generates a recording mock for each of ifaces, for testing
+// host code without a plugin process. The mocks refer to the interfaces
+// named by Generate or GenerateJSONRPC, and are meant to be rendered into a
+// separate file of the same package.
+func (gen *Generator) GenerateMocks(ifaces []*analyzer.Interface) {
+	gen.nameInterfaces(ifaces)
+
+	for _, iface := range ifaces {
+		gen.generateMock(iface)
+	}
+}
+
+func (gen *Generator) generateMock(iface *analyzer.Interface) {
+	interfaceName, _ := gen.interfaceName(iface)
+	mockName := gen.mockName(iface)
+
+	gen.file.Commentf("%s is a mock implementation of %s.", mockName, interfaceName)
+	gen.file.Commentf("Calls are recorded in %s, then handled by the matching function", mockCallsName(iface))
+	gen.file.Comment("field if set, or else return zero values.")
+	gen.file.Type().Id(mockName).StructFunc(func(g *jen.Group) {
+		for _, m := range iface.Methods {
+			g.Id(m.Name + "Func").Func().Add(gen.clientMethodSignature(m))
+		}
+		g.Line()
+		g.Id(mockCallsName(iface)).Qual(runtimePath, "MockRecorder")
+	})
+
+	gen.file.Var().Id("_").Add(tojen.Type(iface.Typ)).Op("=").
+		Parens(jen.Op("*").Id(mockName)).Parens(jen.Nil())
+
+	for _, m := range iface.Methods {
+		gen.generateMockMethod(iface, m)
+	}
+}
+
+// mockCallsName returns the name of the field recording the calls made to
+// the mock of iface, Calls unless a method or function field has that name.
+func mockCallsName(iface *analyzer.Interface) string {
+	taken := map[string]bool{}
+	for _, m := range iface.Methods {
+		taken[m.Name] = true
+		taken[m.Name+"Func"] = true
+	}
+
+	name := "Calls"
+	for i := 2; taken[name]; i++ {
+		name = fmt.Sprintf("Calls%d", i)
+	}
+	return name
+}
+
+func (gen *Generator) generateMockMethod(iface *analyzer.Interface, m *analyzer.Method) {
+	interfaceName, _ := gen.interfaceName(iface)
+	mockName := gen.mockName(iface)
+	funcName := m.Name + "Func"
+
+	gen.file.Commentf("%s implements %s for the %s interface.", m.Name, m.Name, interfaceName)
+	gen.file.Func().
+		Params(jen.Id("m").Op("*").Id(mockName)).
+		Id(m.Name).
+		Add(gen.clientMethodParams(m)).
+		ParamsFunc(func(g *jen.Group) {
+			for i, result := range m.Results {
+				g.Id(resultName(i)).Add(tojen.Type(result.Typ))
+			}
+		}).
+		BlockFunc(func(g *jen.Group) {
+			g.Id("m").Dot(mockCallsName(iface)).Dot("Record").CallFunc(func(g *jen.Group) {
+				g.Lit(m.Name)
+				for i := range m.Params {
+					g.Id(gen.paramName(m, i))
+				}
+			})
+
+			call := jen.Id("m").Dot(funcName).CallFunc(func(g *jen.Group) {
+				for i := range m.Params {
+					if m.Variadic && i == len(m.Params)-1 {
+						g.Id(gen.paramName(m, i)).Op("...")
+						continue
+					}
+					g.Id(gen.paramName(m, i))
+				}
+			})
+
+			if len(m.Results) == 0 {
+				g.If(jen.Id("m").Dot(funcName).Op("!=").Nil()).Block(call)
+				return
+			}
+
+			g.If(jen.Id("m").Dot(funcName).Op("!=").Nil()).Block(jen.Return(call))
+			g.Return()
+		})
+}
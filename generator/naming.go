@@ -2,85 +2,349 @@ package generator
 
 import (
 	"fmt"
+	"go/token"
 	"go/types"
+	"hash/fnv"
+	"io"
+	"strings"
 
 	"github.com/jakebailey/plugingen/analyzer"
 )
 
+// nameInterfaces names ifaces before any code is generated, so that names
+// do not depend on which interface is referred to first. Top-level
+// interfaces are named first, so that they keep their own names if another
+// interface has the same name.
+func (gen *Generator) nameInterfaces(ifaces []*analyzer.Interface) {
+	// Unnamed interfaces are named after the first parameter using them.
+	for _, iface := range ifaces {
+		for _, m := range iface.Methods {
+			for i, param := range m.Params {
+				if param.IFace == nil {
+					continue
+				}
+				if _, ok := param.IFace.Typ.(*types.Named); ok {
+					continue
+				}
+				if _, ok := gen.ifaceParents[param.IFace]; !ok {
+					gen.ifaceParents[param.IFace] = ifaceParent{iface: iface, method: m, param: i}
+				}
+			}
+		}
+	}
+
+	for _, iface := range ifaces {
+		if iface.TopLevel {
+			gen.interfaceName(iface)
+		}
+	}
+	for _, iface := range ifaces {
+		gen.interfaceName(iface)
+	}
+}
+
+// ifaceParent is a parameter using an unnamed interface.
+type ifaceParent struct {
+	iface  *analyzer.Interface
+	method *analyzer.Method
+	param  int
+}
+
 func (gen *Generator) interfaceName(iface *analyzer.Interface) (name string, exists bool) {
 	if name, ok := gen.ifaceNames[iface]; ok {
 		return name, true
 	}
 
+	if iface.Name != "" {
+		gen.ifaceNames[iface] = iface.Name
+		gen.ifaceNamesUsed[iface.Name] = true
+		return iface.Name, false
+	}
+
 	if named, ok := iface.Typ.(*types.Named); ok {
-		name := named.Obj().Name()
+		name := named.Obj().Name() + typeArgsName(named.TypeArgs())
+
+		// Interfaces from different packages may share a name, such as
+		// io.Reader and bufio.Reader; later ones are prefixed by their
+		// package's name.
+		if gen.ifaceNamesUsed[name] && named.Obj().Pkg() != nil {
+			pkgName := named.Obj().Pkg().Name()
+			name = strings.ToUpper(pkgName[:1]) + pkgName[1:] + name
+		}
+
+		for i := 1; gen.ifaceNamesUsed[name]; i++ {
+			name = fmt.Sprintf("%s%s%d", named.Obj().Name(), typeArgsName(named.TypeArgs()), i)
+		}
+
 		gen.ifaceNames[iface] = name
+		gen.ifaceNamesUsed[name] = true
 		return name, false
 	}
 
-	return gen.interfaceNameUnnamed(iface.Typ)
+	return gen.interfaceNameUnnamed(iface)
+}
+
+// typeArgsName returns an identifier describing the type arguments of an
+// instantiated type, so that Store[string, User] can be named StoreStringUser.
+func typeArgsName(args *types.TypeList) string {
+	name := ""
+	for i := 0; i < args.Len(); i++ {
+		name += typeIdent(args.At(i))
+	}
+	return name
+}
+
+// typeIdent returns an exported identifier describing t.
+func typeIdent(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Named:
+		return t.Obj().Name() + typeArgsName(t.TypeArgs())
+	case *types.Alias:
+		return t.Obj().Name() + typeArgsName(t.TypeArgs())
+	case *types.Basic:
+		return strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	case *types.Pointer:
+		return "Ptr" + typeIdent(t.Elem())
+	case *types.Slice:
+		return "Slice" + typeIdent(t.Elem())
+	case *types.Array:
+		return fmt.Sprintf("Array%d%s", t.Len(), typeIdent(t.Elem()))
+	case *types.Map:
+		return "Map" + typeIdent(t.Key()) + typeIdent(t.Elem())
+	case *types.Chan:
+		return "Chan" + typeIdent(t.Elem())
+	case *types.Interface:
+		if t.Empty() {
+			return "Any"
+		}
+	}
+
+	// Describe anything else, such as func and struct types, by a hash of
+	// its type string.
+	h := fnv.New32a()
+	io.WriteString(h, t.String())
+	return fmt.Sprintf("T%x", h.Sum32())
 }
 
-func (gen *Generator) interfaceNameUnnamed(typ types.Type) (name string, exists bool) {
-	t := typ.Underlying().(*types.Interface)
+// interfaceNameUnnamed names an unnamed interface using the interface
+// naming template, after the method and parameter first using it.
+func (gen *Generator) interfaceNameUnnamed(iface *analyzer.Interface) (name string, exists bool) {
+	t := iface.Typ.Underlying().(*types.Interface)
 
 	if name, ok := gen.ifaceUnnamed[t]; ok {
 		return name, true
 	}
 
-	name = fmt.Sprintf("Z_Interface%d", gen.ifaceUnnamedCount)
+	data := NameData{Index: gen.ifaceUnnamedCount}
+	if parent, ok := gen.ifaceParents[iface]; ok {
+		data.Parent, _ = gen.interfaceName(parent.iface)
+		data.Method = parent.method.Name
+		data.Param = exportedName(parent.method.Params[parent.param].Name, parent.param)
+	}
 	gen.ifaceUnnamedCount++
+
+	base := gen.name("interface", data)
+	name = base
+	for i := 2; gen.ifaceNamesUsed[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+
 	gen.ifaceUnnamed[t] = name
+	gen.ifaceNamesUsed[name] = true
 	return name, false
 }
 
+// exportedName returns name with its first letter upper-cased, or P<i> if
+// it is empty or blank.
+func exportedName(name string, i int) string {
+	if name == "" || name == "_" {
+		return fmt.Sprintf("P%d", i)
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// name names an identifier using the naming template for category. If the
+// template fails, the error is recorded to be returned by Err.
+func (gen *Generator) name(category string, data NameData) string {
+	name, err := gen.naming.name(category, data)
+	if err != nil && gen.err == nil {
+		gen.err = err
+	}
+	return name
+}
+
 func (gen *Generator) pluginName(iface *analyzer.Interface) string {
 	name, _ := gen.interfaceName(iface)
-	return name + "Plugin"
+	return gen.name("plugin", NameData{Name: name})
 }
 
 func (gen *Generator) clientName(iface *analyzer.Interface) string {
 	name, _ := gen.interfaceName(iface)
-	return name + "RPCClient"
+	return gen.name("client", NameData{Name: name})
 }
 
 func (gen *Generator) serverName(iface *analyzer.Interface) string {
 	name, _ := gen.interfaceName(iface)
-	return name + "RPCServer"
+	return gen.name("server", NameData{Name: name})
+}
+
+func (gen *Generator) batchName(iface *analyzer.Interface) string {
+	name, _ := gen.interfaceName(iface)
+	return gen.name("batch", NameData{Name: name})
+}
+
+func (gen *Generator) jsonClientName(iface *analyzer.Interface) string {
+	name, _ := gen.interfaceName(iface)
+	return gen.name("jsonclient", NameData{Name: name})
+}
+
+func (gen *Generator) jsonHandlerName(iface *analyzer.Interface) string {
+	name, _ := gen.interfaceName(iface)
+	return gen.name("jsonhandler", NameData{Name: name})
+}
+
+func (gen *Generator) supervisorName(iface *analyzer.Interface) string {
+	name, _ := gen.interfaceName(iface)
+	return gen.name("supervisor", NameData{Name: name})
+}
+
+func (gen *Generator) mockName(iface *analyzer.Interface) string {
+	name, _ := gen.interfaceName(iface)
+	return gen.name("mock", NameData{Name: name})
+}
+
+func (gen *Generator) conformName(iface *analyzer.Interface) string {
+	name, _ := gen.interfaceName(iface)
+	return gen.name("conform", NameData{Name: name})
 }
 
 func (gen *Generator) paramsStructName(iface *analyzer.Interface, m *analyzer.Method) string {
-	interfaceName, _ := gen.interfaceName(iface)
-	return "Z_" + interfaceName + "_" + m.Name + "Params"
+	interfaceName, _ := gen.interfaceName(methodOwner(iface, m))
+	return gen.name("params", NameData{Name: interfaceName, Method: m.Name, Prefix: gen.wirePrefix()})
 }
 
 func (gen *Generator) resultsStructName(iface *analyzer.Interface, m *analyzer.Method) string {
-	interfaceName, _ := gen.interfaceName(iface)
-	return "Z_" + interfaceName + "_" + m.Name + "Results"
+	interfaceName, _ := gen.interfaceName(methodOwner(iface, m))
+	return gen.name("results", NameData{Name: interfaceName, Method: m.Name, Prefix: gen.wirePrefix()})
 }
 
-var paramNameMap = map[int]string{}
+// wirePrefix returns the prefix of the names of the types sent over RPC,
+// which must be exported unless calls are dispatched by the generated code.
+func (gen *Generator) wirePrefix() string {
+	if gen.opts.Unexported {
+		return "z_"
+	}
+	return "Z_"
+}
 
-func paramName(i int) string {
-	if name, ok := paramNameMap[i]; ok {
-		return name
+// serverMethodName returns the name of the server method handling calls to
+// m. Unless calls are dispatched by the generated code, it is served by
+// net/rpc directly, and must be exported.
+func (gen *Generator) serverMethodName(m *analyzer.Method) string {
+	if gen.opts.Unexported {
+		return "serve" + m.Name
 	}
+	return m.Name
+}
 
-	name := fmt.Sprintf("p%d", i)
-	paramNameMap[i] = name
-	return name
+// reservedNames are identifiers declared by generated code in scopes which
+// also declare the parameters of a method.
+var reservedNames = map[string]bool{
+	"b": true, "c": true, "m": true, "s": true, "w": true,
+	"body": true, "call": true, "client": true, "conn": true, "err": true,
+	"id": true, "impl": true, "method": true, "ok": true,
+	paramsStructID: true, resultsStructID: true,
 }
 
-var paramNameExMap = map[int]string{}
+// paramName returns the name of the i'th parameter of m in generated code,
+// from the param naming template. Parameters are named as in the source
+// signature unless any of the names would collide with other identifiers
+// used by the generated code, in which case all are named by position.
+func (gen *Generator) paramName(m *analyzer.Method, i int) string {
+	names, ok := gen.paramNames[m]
+	if !ok {
+		names = gen.methodParamNames(m, true)
+		if !paramNamesUsable(m, names) {
+			names = gen.methodParamNames(m, false)
+		}
+		gen.paramNames[m] = names
+	}
+	return names[i]
+}
 
-func paramNameEx(i int) string {
-	if name, ok := paramNameExMap[i]; ok {
-		return name
+func (gen *Generator) methodParamNames(m *analyzer.Method, source bool) []string {
+	names := make([]string, len(m.Params))
+	for i, param := range m.Params {
+		data := NameData{Index: i}
+		if source && param.Name != "_" {
+			data.Name = param.Name
+		}
+		names[i] = gen.name("param", data)
 	}
+	return names
+}
 
-	name := fmt.Sprintf("P%d", i)
-	paramNameExMap[i] = name
-	return name
+// paramNamesUsable reports whether names, and the identifiers derived from
+// them by generated code, are distinct from each other, from the names of
+// results, and from the identifiers and packages the generated code uses.
+func paramNamesUsable(m *analyzer.Method, names []string) bool {
+	used := map[string]bool{}
+	for _, pkg := range []string{"fmt", "gob", "goplugin", "json", "jsonrpc2", "rpc", "runtime", "time"} {
+		used[pkg] = true
+	}
+
+	qf := func(pkg *types.Package) string {
+		used[pkg.Name()] = true
+		return pkg.Name()
+	}
+	for _, param := range m.Params {
+		types.TypeString(param.Typ, qf)
+	}
+	for i, result := range m.Results {
+		types.TypeString(result.Typ, qf)
+		used[resultName(i)] = true
+	}
+
+	for _, name := range names {
+		for _, n := range []string{name, name + "id", name + "rpc", name + "client"} {
+			if !token.IsIdentifier(n) || reservedNames[n] || types.Universe.Lookup(n) != nil || used[n] {
+				return false
+			}
+			used[n] = true
+		}
+	}
+
+	return true
+}
+
+// paramNameEx returns the name of the field holding the i'th parameter of
+// m in its params struct, from the field naming template. If two fields of
+// the struct would have the same name, an error is recorded.
+func (gen *Generator) paramNameEx(m *analyzer.Method, i int) string {
+	names, ok := gen.fieldNames[m]
+	if !ok {
+		used := map[string]bool{}
+		names = make([]string, len(m.Params))
+		for i, param := range m.Params {
+			data := NameData{Index: i}
+			if param.Name != "_" {
+				data.Name = param.Name
+			}
+			names[i] = gen.name("field", data)
+
+			// Brokered parameters are held in an ID field.
+			field := names[i]
+			if param.IFace != nil {
+				field += "ID"
+			}
+			if used[field] && gen.err == nil {
+				gen.err = fmt.Errorf("field naming template produced %s twice for %s", field, m.Name)
+			}
+			used[field] = true
+		}
+		gen.fieldNames[m] = names
+	}
+	return names[i]
 }
 
 var resultNameMap = map[int]string{}
@@ -106,3 +370,45 @@ func resultNameEx(i int) string {
 	resultNameExMap[i] = name
 	return name
 }
+
+// methodOwner returns the interface whose generated code implements m for
+// iface. If exactly one of the interfaces embedded in iface has m, that
+// interface's code is shared; otherwise, iface implements m itself.
+func methodOwner(iface *analyzer.Interface, m *analyzer.Method) *analyzer.Interface {
+	var owner *analyzer.Interface
+	for _, e := range iface.Embeds {
+		for _, em := range e.Methods {
+			if em.Name != m.Name {
+				continue
+			}
+			if owner != nil {
+				return iface
+			}
+			owner = e
+		}
+	}
+
+	if owner == nil {
+		return iface
+	}
+	return methodOwner(owner, m)
+}
+
+// capabilitiesName returns the name of the client returned by
+// WithCapabilities for iface when the plugin implements the optional
+// interfaces selected by mask.
+func (gen *Generator) capabilitiesName(iface *analyzer.Interface, mask int) string {
+	name, _ := gen.interfaceName(iface)
+	name = strings.ToLower(name[:1]) + name[1:] + "With"
+	for i, opt := range iface.Optional {
+		if mask&(1<<i) != 0 {
+			optName, _ := gen.interfaceName(opt)
+			name += optName
+		}
+	}
+	return name
+}
+
+func optionalName(i int) string {
+	return fmt.Sprintf("opt%d", i)
+}
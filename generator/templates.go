@@ -0,0 +1,188 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// DefaultNaming holds the default naming template of each category of
+// generated identifiers, which can be overridden with ParseNaming.
+var DefaultNaming = map[string]string{
+	"plugin":      "{{.Name}}Plugin",
+	"client":      "{{.Name}}RPCClient",
+	"server":      "{{.Name}}RPCServer",
+	"supervisor":  "{{.Name}}Supervisor",
+	"batch":       "{{.Name}}Batch",
+	"mock":        "{{.Name}}Mock",
+	"conform":     "Conform{{.Name}}",
+	"jsonclient":  "{{.Name}}JSONClient",
+	"jsonhandler": "{{.Name}}JSONHandler",
+	"params":      "{{.Prefix}}{{.Name}}_{{.Method}}Params",
+	"results":     "{{.Prefix}}{{.Name}}_{{.Method}}Results",
+	"interface":   "{{if .Parent}}{{.Parent}}{{.Method}}{{.Param}}{{else}}Z_Interface{{.Index}}{{end}}",
+	"param":       "{{if .Name}}{{.Name}}{{else}}p{{.Index}}{{end}}",
+	"field":       "P{{.Index}}",
+}
+
+// NameData is passed to naming templates.
+type NameData struct {
+	// Name is the name of the interface for the categories naming types,
+	// or the name of the parameter in the source signature, if any, for
+	// param and field.
+	Name string
+
+	// Method is the name of the method for params, results, and interface.
+	Method string
+
+	// Parent and Param name the interface and exported parameter an unnamed
+	// interface is first used by, for interface. They are empty if the
+	// interface is not used as a parameter.
+	Parent string
+	Param  string
+
+	// Index is the position of the parameter for param and field, or the
+	// number of the unnamed interface for interface.
+	Index int
+
+	// Prefix is Z_, or z_ with unexported wire types, for params and
+	// results.
+	Prefix string
+}
+
+// namingFuncs are the functions available to naming templates.
+var namingFuncs = template.FuncMap{
+	// title upper-cases the first letter of s, as needed to export it.
+	"title": func(s string) string {
+		if s == "" {
+			return s
+		}
+		return strings.ToUpper(s[:1]) + s[1:]
+	},
+}
+
+// exportedNaming holds the categories whose names must be exported, as
+// net/rpc ignores methods taking unexported types, and gob ignores
+// unexported fields. Names of params and results are not checked when
+// rendered with the z_ prefix, as calls are then dispatched by generated
+// code.
+var exportedNaming = map[string]bool{
+	"params":  true,
+	"results": true,
+	"field":   true,
+}
+
+// namingBackends holds the categories of types generated for only one
+// backend, which may share names with types of the other backend.
+var namingBackends = map[string]string{
+	"client":      "netrpc",
+	"server":      "netrpc",
+	"supervisor":  "netrpc",
+	"batch":       "netrpc",
+	"params":      "netrpc",
+	"results":     "netrpc",
+	"jsonclient":  "jsonrpc",
+	"jsonhandler": "jsonrpc",
+}
+
+// Naming holds the templates used to name generated identifiers.
+type Naming struct {
+	templates map[string]*template.Template
+}
+
+// ParseNaming parses naming templates keyed by category, which override
+// the templates in DefaultNaming. Each template is checked by executing it
+// with sample data, which must produce an identifier, exported if net/rpc
+// or gob require it, and types of different categories must not be given
+// the same name. Templates may call title to upper-case the first letter of
+// a name.
+func ParseNaming(overrides map[string]string) (*Naming, error) {
+	n := &Naming{templates: map[string]*template.Template{}}
+
+	for category, text := range DefaultNaming {
+		n.templates[category] = template.Must(template.New(category).Funcs(namingFuncs).Parse(text))
+	}
+
+	for category, text := range overrides {
+		if _, ok := DefaultNaming[category]; !ok {
+			return nil, fmt.Errorf("unknown naming category %q; expected one of %s", category, strings.Join(NamingCategories(), ", "))
+		}
+
+		t, err := template.New(category).Funcs(namingFuncs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s naming template: %v", category, err)
+		}
+		n.templates[category] = t
+	}
+
+	// Check every category, as a default template may collide with an
+	// override.
+	sample := NameData{Name: "Thinger", Method: "Thing", Parent: "Thinger", Param: "Fn", Index: 1, Prefix: "Z_"}
+	types := map[string]string{}
+	for _, category := range NamingCategories() {
+		name, err := n.name(category, sample)
+		if err != nil {
+			return nil, err
+		}
+
+		switch category {
+		case "param", "field":
+			// Check positional names too.
+			data := sample
+			data.Name = ""
+			if _, err := n.name(category, data); err != nil {
+				return nil, err
+			}
+			continue
+
+		case "interface":
+			// Check interfaces not used as parameters too.
+			if _, err := n.name(category, NameData{Index: 1}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for other, otherName := range types {
+			b1, b2 := namingBackends[category], namingBackends[other]
+			if name == otherName && (b1 == "" || b2 == "" || b1 == b2) {
+				return nil, fmt.Errorf("%s and %s naming templates both produce %s", other, category, name)
+			}
+		}
+		types[category] = name
+	}
+
+	return n, nil
+}
+
+// NamingCategories returns the sorted categories of DefaultNaming.
+func NamingCategories() []string {
+	categories := make([]string, 0, len(DefaultNaming))
+	for category := range DefaultNaming {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// name executes the template for category, returning an error if it fails
+// or does not produce a usable name.
+func (n *Naming) name(category string, data NameData) (string, error) {
+	var buf strings.Builder
+	if err := n.templates[category].Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("executing %s naming template: %v", category, err)
+	}
+
+	name := buf.String()
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("%s naming template produced %q, which is not an identifier", category, name)
+	}
+
+	if exportedNaming[category] && data.Prefix != "z_" && !token.IsExported(name) {
+		return "", fmt.Errorf("%s naming template produced %s, which must be exported", category, name)
+	}
+
+	return name, nil
+}
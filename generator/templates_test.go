@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNaming(t *testing.T) {
+	tests := []struct {
+		overrides map[string]string
+		err       string
+	}{
+		{map[string]string{"client": "{{.Name}}Client", "jsonclient": "{{.Name}}Client"}, ""},
+		{map[string]string{"field": "{{if .Name}}{{title .Name}}{{else}}Arg{{.Index}}{{end}}"}, ""},
+		{map[string]string{"params": "{{.Prefix}}{{.Name}}{{.Method}}Args"}, ""},
+		{map[string]string{"bogus": "x"}, `unknown naming category "bogus"`},
+		{map[string]string{"client": "{{.Name"}, "parsing client naming template"},
+		{map[string]string{"client": "{{.Nope}}"}, "executing client naming template"},
+		{map[string]string{"client": ""}, `client naming template produced "", which is not an identifier`},
+		{map[string]string{"server": "{{.Name}}-Server"}, "not an identifier"},
+		{map[string]string{"param": "{{.Name}}"}, `param naming template produced ""`},
+		{map[string]string{"field": "{{.Name}}"}, `field naming template produced "", which is not an identifier`},
+		{map[string]string{"field": "p{{.Index}}"}, "field naming template produced p1, which must be exported"},
+		{map[string]string{"params": "args{{.Name}}{{.Method}}"}, "params naming template produced argsThingerThing, which must be exported"},
+		{map[string]string{"server": "{{.Name}}RPCClient"}, "client and server naming templates both produce ThingerRPCClient"},
+		{map[string]string{"params": "{{.Prefix}}{{.Name}}_{{.Method}}Results"}, "params and results naming templates both produce Z_Thinger_ThingResults"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseNaming(test.overrides)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("ParseNaming(%v) = %v", test.overrides, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("ParseNaming(%v) = %v; want an error containing %q", test.overrides, err, test.err)
+		}
+	}
+}
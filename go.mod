@@ -1,8 +1,22 @@
 module github.com/jakebailey/plugingen
 
+go 1.23
+
 require (
 	github.com/dave/jennifer v1.3.0
 	github.com/hashicorp/go-hclog v0.7.0
 	github.com/hashicorp/go-plugin v0.0.0-20190220160451-3f118e8ee104
 	golang.org/x/tools v0.0.0-20190226205152-f727befe758c
 )
+
+require (
+	github.com/golang/protobuf v1.2.0 // indirect
+	github.com/hashicorp/yamux v0.0.0-20180604194846-3520598351bb // indirect
+	github.com/mitchellh/go-testing-interface v0.0.0-20171004221916-a61a99592b77 // indirect
+	github.com/oklog/run v1.0.0 // indirect
+	golang.org/x/net v0.0.0-20190213061140-3a22650c66bd // indirect
+	golang.org/x/sys v0.0.0-20190129075346-302c3dd5f1cc // indirect
+	golang.org/x/text v0.3.0 // indirect
+	google.golang.org/genproto v0.0.0-20180817151627-c66870c02cf8 // indirect
+	google.golang.org/grpc v1.14.0 // indirect
+)
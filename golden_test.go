@@ -0,0 +1,120 @@
+package plugingen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// TestGolden generates code for each directory in testdata/golden, which
+// holds the input package and a config.json decoded into Config, then
+// compares each generated file to the expected output in the directory's
+// plug subpackage. Each output is also built and vetted, and tested if the
+// plug subpackage holds tests of its own.
+func TestGolden(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("testdata", "golden", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range dirs {
+		dir := dir
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			b, err := ioutil.ReadFile(filepath.Join(dir, "config.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var config Config
+			if err := json.Unmarshal(b, &config); err != nil {
+				t.Fatal(err)
+			}
+			config.Args = []string{"./" + filepath.ToSlash(dir)}
+			config.SubPkg = "plug"
+
+			files, _, err := Generate(context.Background(), config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			name := filepath.Join(dir, "plug", "plugingen.go")
+			if _, ok := files[name]; !ok {
+				t.Fatalf("Generate() did not produce %s", name)
+			}
+
+			names := make([]string, 0, len(files))
+			for name := range files {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				got := files[name]
+				if got == nil {
+					t.Errorf("%s is stale; remove it", name)
+					continue
+				}
+
+				if *update {
+					if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+						t.Fatal(err)
+					}
+					if err := ioutil.WriteFile(name, got, 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				want, err := ioutil.ReadFile(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !bytes.Equal(got, want) {
+					t.Errorf("%s differs from generated output, first at line %d; run go test -update to update it", name, firstDiff(got, want))
+				}
+			}
+
+			plug := "./" + filepath.ToSlash(filepath.Join(dir, "plug"))
+
+			out, err := exec.Command("go", "vet", plug).CombinedOutput()
+			if err != nil {
+				t.Errorf("go vet failed: %v\n%s", err, out)
+			}
+
+			tests, err := filepath.Glob(filepath.Join(dir, "plug", "*_test.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tests) != 0 {
+				out, err := exec.Command("go", "test", plug).CombinedOutput()
+				if err != nil {
+					t.Errorf("go test failed: %v\n%s", err, out)
+				}
+			}
+		})
+	}
+}
+
+// firstDiff returns the first line number at which a and b differ.
+func firstDiff(a, b []byte) int {
+	al := strings.Split(string(a), "\n")
+	bl := strings.Split(string(b), "\n")
+
+	for i := range al {
+		if i >= len(bl) || al[i] != bl[i] {
+			return i + 1
+		}
+	}
+	return len(al) + 1
+}
@@ -2,33 +2,60 @@ package loader
 
 import (
 	"errors"
+	"fmt"
+	"go/ast"
 	"go/build"
+	"go/parser"
+	"go/token"
 	"go/types"
-	"log"
 	"os"
 	"path/filepath"
 
 	"golang.org/x/tools/go/loader"
+	"golang.org/x/tools/go/packages"
 )
 
 var ErrTagsNotApplicable = errors.New("build tags only apply to directories, not when files are specified")
 
-func LoadPackage(buildTags []string, args []string) (pkg *types.Package, dir string, err error) {
+// Package is a loaded and type-checked package.
+type Package struct {
+	Types *types.Package
+	Fset  *token.FileSet
+	Dir   string
+
+	// Files contains the syntax of the package and all of its dependencies.
+	Files []*ast.File
+}
+
+func LoadPackage(buildTags []string, args []string) (*Package, error) {
 	if len(args) == 0 {
 		args = []string{"."}
 	}
 
 	conf := loader.Config{
 		Build:               buildContext(buildTags),
+		ParserMode:          parser.ParseComments,
 		TypeCheckFuncBodies: func(string) bool { return false },
 	}
 
-	if len(args) == 1 && isDirectory(args[0]) {
+	var dir string
+
+	isDir, err := isDirectory(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 1 && isDir {
 		dir = args[0]
-		conf.Import(dir)
+
+		path, err := importPath(dir)
+		if err != nil {
+			return nil, err
+		}
+		conf.Import(path)
 	} else {
 		if len(buildTags) != 0 {
-			return nil, "", ErrTagsNotApplicable
+			return nil, ErrTagsNotApplicable
 		}
 		dir = filepath.Dir(args[0])
 		conf.CreateFromFilenames("", args...)
@@ -36,10 +63,20 @@ func LoadPackage(buildTags []string, args []string) (pkg *types.Package, dir str
 
 	lprog, err := conf.Load()
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, info := range lprog.AllPackages {
+		files = append(files, info.Files...)
 	}
 
-	return lprog.InitialPackages()[0].Pkg, dir, nil
+	return &Package{
+		Types: lprog.InitialPackages()[0].Pkg,
+		Fset:  lprog.Fset,
+		Dir:   dir,
+		Files: files,
+	}, nil
 }
 
 func buildContext(tags []string) *build.Context {
@@ -48,11 +85,31 @@ func buildContext(tags []string) *build.Context {
 	return &ctx
 }
 
+// importPath resolves the import path of the package in dir. go/build leaves
+// relative paths as-is in module mode, which would be rendered into the
+// generated code.
+func importPath(dir string) (string, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadFiles, Dir: dir}, ".")
+	if err != nil {
+		return "", err
+	}
+
+	if len(pkgs) != 1 {
+		return "", fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	if len(pkgs[0].Errors) != 0 {
+		return "", pkgs[0].Errors[0]
+	}
+
+	return pkgs[0].PkgPath, nil
+}
+
 // isDirectory reports whether the named file is a directory.
-func isDirectory(name string) bool {
+func isDirectory(name string) (bool, error) {
 	info, err := os.Stat(name)
 	if err != nil {
-		log.Fatal(err)
+		return false, err
 	}
-	return info.IsDir()
+	return info.IsDir(), nil
 }
@@ -0,0 +1,528 @@
+// Package plugingen generates code to use arbitrary interfaces with
+// hashicorp's go-plugin.
+//
+// The plugingen command is a thin wrapper around Generate; see
+// github.com/jakebailey/plugingen/cmd/plugingen.
+package plugingen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/jakebailey/plugingen/analyzer"
+	"github.com/jakebailey/plugingen/generator"
+	"github.com/jakebailey/plugingen/loader"
+	"github.com/jakebailey/plugingen/schema"
+)
+
+// Diagnostic is a problem found while analyzing the input interfaces.
+type Diagnostic = analyzer.Diagnostic
+
+var (
+	// ErrNoTypes is returned by Generate when Config.Types is empty.
+	ErrNoTypes = errors.New("no types specified")
+
+	// ErrBatchCodec is returned by Generate when batching is enabled with a
+	// codec other than gob.
+	ErrBatchCodec = errors.New("batching requires the gob codec")
+
+	// ErrBatchUnexported is returned by Generate when batching is enabled
+	// with unexported wire types, as batch clients return the result types.
+	ErrBatchUnexported = errors.New("batching requires exported wire types")
+
+	// ErrSchemaUnexported is returned by Schema when unexported wire types
+	// are enabled, as their calls are dispatched through a single method
+	// which the schema does not describe.
+	ErrSchemaUnexported = errors.New("schemas cannot describe unexported wire types")
+
+	// ErrBackendOption is returned by Generate when an option which only
+	// applies to the netrpc backend is used with the jsonrpc backend.
+	ErrBackendOption = errors.New("supervisors, batching, codecs, timeouts, embedded composition, optional interfaces, unexported wire types and conformance tests require the netrpc backend")
+)
+
+// Config configures a call to Generate.
+type Config struct {
+	// Types lists the names of the interface types to generate plugins for.
+	// The types must be declared in the loaded package. Generic types must be
+	// instantiated, as in "Store[string, User]". Required.
+	Types []string
+
+	// Args is the directory, or list of files in a single package, to load
+	// the types from. Defaults to the current directory.
+	Args []string
+
+	// BuildTags are the build tags to apply when loading a directory.
+	BuildTags []string
+
+	// Output is the name of the generated file. Defaults to plugingen.go in
+	// the output directory.
+	Output string
+
+	// SubPkg is the name of a subpackage of the loaded package to generate
+	// code into. If set, the output directory is <dir>/<SubPkg>.
+	SubPkg string
+
+	// AllowError disables wrapping errors with plugin.BasicError.
+	AllowError bool
+
+	// RPCPanic makes generated clients exit on RPC call errors, rather than
+	// logging them.
+	RPCPanic bool
+
+	// Timeout is the default timeout for calls made by generated clients. If
+	// zero, calls wait forever unless a method has its own timeout.
+	Timeout time.Duration
+
+	// TimeoutClose makes generated clients close their connection to the
+	// plugin after a call times out, so that later calls fail immediately.
+	TimeoutClose bool
+
+	// Supervisor generates a supervisor wrapper for each of Types, which
+	// restarts the plugin process if the connection to it is lost.
+	Supervisor bool
+
+	// Batch generates batch clients, which queue calls and send them to the
+	// plugin in a single RPC.
+	Batch bool
+
+	// Codec is the name of the runtime codec used for RPC connections, such
+	// as "json", or a codec added with runtime.RegisterCodec. Defaults to
+	// gob. Other codecs cannot encode interface values, so errors are always
+	// sent as plugin.BasicError, and batching is unavailable.
+	Codec string
+
+	// Backend selects the generated code: "netrpc" (the default) for
+	// go-plugin, or "jsonrpc" for JSON-RPC 2.0 clients which talk to plugins
+	// written in other languages. The jsonrpc backend also outputs a schema
+	// describing the interfaces, named after Output with a .schema.json
+	// extension. Supervisor, Batch, Codec, Timeout and TimeoutClose apply
+	// only to netrpc.
+	Backend string
+
+	// Embedded generates code for the methods of named interfaces embedded
+	// in the analyzed interfaces once, as a client and server for each
+	// embedded interface, which the embedding interfaces' clients and servers
+	// embed. Only applies to the netrpc backend.
+	Embedded bool
+
+	// Docs also generates PLUGIN_API.md in the output directory, documenting
+	// the interfaces for plugin authors.
+	Docs bool
+
+	// Mocks also generates recording mocks of each interface, for testing
+	// host code without a plugin process. They are output next to Output,
+	// with a _mock.go suffix.
+	Mocks bool
+
+	// Conformance also generates a test file containing a conformance
+	// harness for each of Types, which checks that calls made through the
+	// generated client return the same results as calls made to an
+	// implementation directly. It is output next to Output, with a _test.go
+	// suffix.
+	Conformance bool
+
+	// Unexported keeps the types holding the parameters and results of
+	// methods unexported, so that they do not clutter the generated
+	// package's documentation. Calls are then sent to a single Call method
+	// of each server, which decodes them itself. Clients and servers must
+	// agree on the setting. Only applies to the netrpc backend.
+	Unexported bool
+
+	// Naming overrides the text/template used to name each category of
+	// generated identifiers, such as "client" or "params"; see
+	// generator.DefaultNaming for the categories and their defaults.
+	Naming map[string]string
+
+	// Split generates the code for each interface into its own file, named
+	// after the interface and Output, such as thinger_plugingen.go. Code
+	// shared by the interfaces, such as the handshake, is still generated
+	// into Output. Mocks, conformance tests and docs are not split.
+	Split bool
+
+	// Command is the command line recorded in the generated file's header.
+	// Defaults to a command line derived from Types.
+	Command string
+
+	// Logger, if set, is told about each interface as it is generated.
+	Logger *log.Logger
+}
+
+// analyze loads the package configured by config, then analyzes its types.
+func analyze(ctx context.Context, config Config) (*loader.Package, []*analyzer.Interface, []Diagnostic, error) {
+	if len(config.Types) == 0 {
+		return nil, nil, nil, ErrNoTypes
+	}
+
+	lpkg, err := loader.LoadPackage(config.BuildTags, config.Args)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	a := analyzer.NewAnalyzer(config.AllowError, lpkg.Fset, lpkg.Files)
+	a.SetEmbedded(config.Embedded)
+
+	typeList := make([]types.Type, len(config.Types))
+	for i, name := range config.Types {
+		typ, err := lookupType(lpkg, name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		typeList[i] = typ
+	}
+
+	ifaces, diags, err := a.AnalyzeAll(typeList)
+	if err != nil {
+		return nil, nil, diags, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, diags, err
+	}
+
+	return lpkg, ifaces, diags, nil
+}
+
+// lookupType finds the named type in lpkg. Generic types must be
+// instantiated, as in Store[string, User]; type arguments are resolved in the
+// scope of the file declaring the generic type.
+func lookupType(lpkg *loader.Package, name string) (types.Type, error) {
+	pkg := lpkg.Types
+
+	base := name
+	if i := strings.Index(name, "["); i >= 0 {
+		base = strings.TrimSpace(name[:i])
+	}
+
+	obj := pkg.Scope().Lookup(base)
+	if obj == nil {
+		return nil, fmt.Errorf("%s.%s not found", pkg.Path(), base)
+	}
+
+	typ := obj.Type()
+
+	if base != name {
+		tv, err := types.Eval(lpkg.Fset, pkg, obj.Pos(), name)
+		if err != nil {
+			return nil, fmt.Errorf("instantiating %s: %v", name, err)
+		}
+		typ = tv.Type
+	}
+
+	if named, ok := types.Unalias(typ).(*types.Named); ok && named.TypeParams().Len() != named.TypeArgs().Len() {
+		return nil, fmt.Errorf("%s.%s is generic, and must be instantiated, as in %s[T]", pkg.Path(), base, base)
+	}
+
+	return typ, nil
+}
+
+// Schema describes the wire protocol of the plugins configured by config,
+// for the configured backend. Only Types, Args, BuildTags, AllowError,
+// Backend, Codec, Embedded and Naming are used. If Unexported is set,
+// ErrSchemaUnexported is returned.
+func Schema(ctx context.Context, config Config) (*schema.Schema, []Diagnostic, error) {
+	if config.Unexported {
+		return nil, nil, ErrSchemaUnexported
+	}
+
+	codec := config.Codec
+	if codec == "gob" {
+		codec = ""
+	}
+
+	protocol := "netrpc"
+	switch config.Backend {
+	case "", "netrpc":
+	case "jsonrpc":
+		if codec != "" || config.Embedded {
+			return nil, nil, ErrBackendOption
+		}
+		protocol = "jsonrpc2"
+	default:
+		return nil, nil, fmt.Errorf("unknown backend %q", config.Backend)
+	}
+
+	naming, err := generator.ParseNaming(config.Naming)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, ifaces, diags, err := analyze(ctx, config)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	g := generator.NewGenerator(jen.NewFile("schema"), generator.Options{Codec: codec, Naming: naming})
+	sch := g.Schema(protocol, ifaces)
+	if err := g.Err(); err != nil {
+		return nil, diags, err
+	}
+	return sch, diags, nil
+}
+
+// Generate generates plugin code as configured, returning the rendered
+// files keyed by path. Nothing is written to disk. Files split from Output
+// by an earlier run with Split which are no longer generated are included
+// with nil contents, and should be removed. Diagnostics are returned even if
+// an error occurs during analysis.
+func Generate(ctx context.Context, config Config) (map[string][]byte, []Diagnostic, error) {
+	if len(config.Types) == 0 {
+		return nil, nil, ErrNoTypes
+	}
+
+	codec := config.Codec
+	if codec == "gob" {
+		codec = ""
+	}
+
+	if codec != "" && config.Batch {
+		return nil, nil, ErrBatchCodec
+	}
+
+	if config.Unexported && config.Batch {
+		return nil, nil, ErrBatchUnexported
+	}
+
+	naming, err := generator.ParseNaming(config.Naming)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch config.Backend {
+	case "", "netrpc":
+	case "jsonrpc":
+		if config.Supervisor || config.Batch || codec != "" || config.Embedded || config.Unexported || config.Conformance ||
+			config.Timeout != 0 || config.TimeoutClose {
+			return nil, nil, ErrBackendOption
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown backend %q", config.Backend)
+	}
+
+	lpkg, ifaces, diags, err := analyze(ctx, config)
+	if err != nil {
+		return nil, diags, err
+	}
+
+	if config.Backend == "jsonrpc" {
+		for _, iface := range ifaces {
+			if len(iface.Optional) != 0 {
+				return nil, diags, ErrBackendOption
+			}
+		}
+	}
+
+	pkg := lpkg.Types
+	dir := lpkg.Dir
+
+	pkgPath := pkg.Path()
+	if config.SubPkg != "" {
+		pkgPath += "/" + config.SubPkg
+		dir = filepath.Join(dir, config.SubPkg)
+	}
+
+	command := config.Command
+	if command == "" {
+		command = "plugingen -type=" + strings.Join(config.Types, ",")
+	}
+
+	newFile := func() *jen.File {
+		file := jen.NewFilePath(pkgPath)
+		file.PackageComment(fmt.Sprintf("// Code generated by \"%s\"; DO NOT EDIT.\n", command))
+		return file
+	}
+
+	opts := generator.Options{
+		RPCPanic:     config.RPCPanic,
+		Timeout:      config.Timeout,
+		TimeoutClose: config.TimeoutClose,
+		Supervisor:   config.Supervisor,
+		Batch:        config.Batch,
+		Codec:        codec,
+		Unexported:   config.Unexported,
+		PkgPath:      pkgPath,
+		Naming:       naming,
+		Logger:       config.Logger,
+	}
+	if config.Split {
+		opts.NewFile = newFile
+	}
+
+	file := newFile()
+	g := generator.NewGenerator(file, opts)
+
+	var sch *schema.Schema
+	if config.Backend == "jsonrpc" {
+		sch = g.GenerateJSONRPC(ifaces)
+	} else {
+		g.Generate(ifaces)
+	}
+
+	if err := g.Err(); err != nil {
+		return nil, diags, err
+	}
+
+	var buf bytes.Buffer
+	if err := file.Render(&buf); err != nil {
+		return nil, diags, err
+	}
+
+	outputName := config.Output
+	if outputName == "" {
+		outputName = filepath.Join(dir, "plugingen.go")
+	}
+
+	files := map[string][]byte{outputName: buf.Bytes()}
+
+	if config.Split {
+		split := g.Files()
+		names := make([]string, 0, len(split))
+		for name := range split {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		used := map[string]bool{}
+		for _, name := range names {
+			var buf bytes.Buffer
+			if err := split[name].Render(&buf); err != nil {
+				return nil, diags, err
+			}
+			files[splitFileName(outputName, name, used)] = buf.Bytes()
+		}
+	}
+
+	stale, err := staleFiles(outputName, files)
+	if err != nil {
+		return nil, diags, err
+	}
+	for _, name := range stale {
+		files[name] = nil
+	}
+
+	if config.Mocks {
+		mockFile := jen.NewFilePath(pkgPath)
+		mockFile.PackageComment(fmt.Sprintf("// Code generated by \"%s\"; DO NOT EDIT.\n", command))
+
+		mg := generator.NewGenerator(mockFile, generator.Options{Naming: naming})
+		mg.GenerateMocks(ifaces)
+		if err := mg.Err(); err != nil {
+			return nil, diags, err
+		}
+
+		var mockBuf bytes.Buffer
+		if err := mockFile.Render(&mockBuf); err != nil {
+			return nil, diags, err
+		}
+		files[strings.TrimSuffix(outputName, ".go")+"_mock.go"] = mockBuf.Bytes()
+	}
+
+	if config.Conformance {
+		testFile := jen.NewFilePath(pkgPath)
+		testFile.PackageComment(fmt.Sprintf("// Code generated by \"%s\"; DO NOT EDIT.\n", command))
+
+		cg := generator.NewGenerator(testFile, generator.Options{Naming: naming})
+		cg.GenerateConformance(ifaces)
+		if err := cg.Err(); err != nil {
+			return nil, diags, err
+		}
+
+		var testBuf bytes.Buffer
+		if err := testFile.Render(&testBuf); err != nil {
+			return nil, diags, err
+		}
+		files[strings.TrimSuffix(outputName, ".go")+"_test.go"] = testBuf.Bytes()
+	}
+
+	if config.Docs {
+		docs := g.Docs(command, ifaces, diags, config.Backend != "jsonrpc")
+		if err := g.Err(); err != nil {
+			return nil, diags, err
+		}
+		files[filepath.Join(filepath.Dir(outputName), "PLUGIN_API.md")] = docs
+	}
+
+	if sch != nil {
+		b, err := json.MarshalIndent(sch, "", "\t")
+		if err != nil {
+			return nil, diags, err
+		}
+		files[strings.TrimSuffix(outputName, ".go")+".schema.json"] = append(b, '\n')
+	}
+
+	return files, diags, nil
+}
+
+// splitFileName returns the name of the file split from output for the
+// interface with the given name, such as thinger_plugingen.go. Names which
+// differ only in case are numbered, so that they do not collide on
+// case-insensitive file systems.
+func splitFileName(output, name string, used map[string]bool) string {
+	base := strings.ToLower(name)
+	lower := base
+	for i := 2; used[lower]; i++ {
+		lower = fmt.Sprintf("%s%d", base, i)
+	}
+	used[lower] = true
+
+	return filepath.Join(filepath.Dir(output), lower+"_"+filepath.Base(output))
+}
+
+// runtimePath is the import path of the runtime package, which all
+// generated code imports.
+const runtimePath = "github.com/jakebailey/plugingen/runtime"
+
+// staleFiles returns the files split from output by an earlier run which are
+// not among files. They are recognized by their names, and by being
+// generated code in output's package which imports the runtime package, so
+// that they are found whatever command generated them.
+func staleFiles(output string, files map[string][]byte) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(output), "*_"+filepath.Base(output)))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	out, err := parser.ParseFile(fset, output, files[output], parser.PackageClauseOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	for _, name := range matches {
+		if _, ok := files[name]; ok {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, name, nil, parser.ImportsOnly|parser.ParseComments)
+		if err != nil || !ast.IsGenerated(f) || f.Name.Name != out.Name.Name {
+			continue
+		}
+
+		for _, imp := range f.Imports {
+			if path, _ := strconv.Unquote(imp.Path.Value); path == runtimePath || strings.HasPrefix(path, runtimePath+"/") {
+				stale = append(stale, name)
+				break
+			}
+		}
+	}
+
+	return stale, nil
+}
@@ -0,0 +1,41 @@
+package plugingen
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBackendOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"Timeout", Config{Timeout: time.Second}},
+		{"TimeoutClose", Config{TimeoutClose: true}},
+		{"Supervisor", Config{Supervisor: true}},
+		{"Codec", Config{Codec: "json"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := test.config
+			config.Types = []string{"Store"}
+			config.Args = []string{"./testdata/golden/jsonrpc"}
+			config.Backend = "jsonrpc"
+
+			if _, _, err := Generate(context.Background(), config); err != ErrBackendOption {
+				t.Errorf("Generate() = %v; want %v", err, ErrBackendOption)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for _, dir := range []string{"./testdata/missing", "./testdata/golden"} {
+		config := Config{Types: []string{"Store"}, Args: []string{dir}}
+		if _, _, err := Generate(context.Background(), config); err == nil {
+			t.Errorf("Generate() with Args %s succeeded; want an error", dir)
+		}
+	}
+}
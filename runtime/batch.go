@@ -0,0 +1,92 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// BatchCall is a single call sent as part of a batch.
+type BatchCall struct {
+	Method string
+	Params interface{}
+}
+
+// BatchResult is the result of a single call sent as part of a batch.
+type BatchResult struct {
+	Results interface{}
+	Error   error
+}
+
+// Batch holds calls queued by a generated batch client.
+type Batch struct {
+	calls   []BatchCall
+	results []interface{}
+}
+
+// Add queues a call to the named method. Params and results must be
+// pointers to types registered with gob, or nil.
+func (b *Batch) Add(method string, params, results interface{}) {
+	b.calls = append(b.calls, BatchCall{Method: method, Params: params})
+	b.results = append(b.results, results)
+}
+
+// Len returns the number of queued calls.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// Flush sends the calls queued in b to the plugin in a single RPC, then
+// copies their results into the values given to Add. The calls are executed
+// in order, stopping at the first to fail; its error is passed to the
+// client's ErrorHandler before being returned. b is empty afterwards.
+func (c *Client) Flush(b *Batch) error {
+	calls, dsts := b.calls, b.results
+	b.calls, b.results = nil, nil
+
+	if len(calls) == 0 {
+		return nil
+	}
+
+	var results []BatchResult
+	if err := c.Call("Z_Batch", calls, &results); err != nil {
+		return err
+	}
+
+	for i, result := range results {
+		if result.Error != nil {
+			c.onError(c.name+"."+calls[i].Method, result.Error)
+			return result.Error
+		}
+
+		if dsts[i] != nil && result.Results != nil {
+			reflect.ValueOf(dsts[i]).Elem().Set(reflect.ValueOf(result.Results).Elem())
+		}
+	}
+
+	if len(results) != len(calls) {
+		err := fmt.Errorf("batch returned %d results for %d calls", len(results), len(calls))
+		c.onError(c.name+".Z_Batch", err)
+		return err
+	}
+
+	return nil
+}
+
+// ServeBatch executes calls in order using call, which returns a pointer to
+// the results of a single call. Execution stops at the first call to fail.
+// It implements the server side of a generated Z_Batch method.
+func ServeBatch(calls []BatchCall, results *[]BatchResult, call func(BatchCall) (interface{}, error)) error {
+	*results = make([]BatchResult, 0, len(calls))
+
+	for _, c := range calls {
+		r, err := call(c)
+		if err != nil {
+			*results = append(*results, BatchResult{Error: WrapError(err)})
+			break
+		}
+
+		*results = append(*results, BatchResult{Results: r})
+	}
+
+	return nil
+}
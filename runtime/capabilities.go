@@ -0,0 +1,91 @@
+package runtime
+
+import "fmt"
+
+// Attach ties child, a client for the optional interface named name which
+// was dialed from an ID returned by the plugin's Z_Capabilities method, to
+// c's connection. When c's connection is replaced, child's connection is
+// closed, as it belongs to the old plugin, and child asks the plugin to
+// serve the interface again on its next call.
+func (c *Client) Attach(name string, child *Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	child.mu.Lock()
+	child.parent, child.capability, child.parentGen = c, name, c.gen
+	child.mu.Unlock()
+
+	c.children = append(c.children, child)
+}
+
+// closeChildren closes the connections of the clients attached to c. c.mu
+// must be held.
+func (c *Client) closeChildren() {
+	for _, child := range c.children {
+		child.mu.RLock()
+		child.client.Close()
+		child.mu.RUnlock()
+	}
+	c.children = nil
+}
+
+// refresh redials c if it is attached to a client whose connection has been
+// replaced since c was dialed.
+func (c *Client) refresh() error {
+	c.mu.RLock()
+	parent, parentGen := c.parent, c.parentGen
+	c.mu.RUnlock()
+
+	if parent == nil {
+		return nil
+	}
+
+	if _, _, gen := parent.conn(); gen != parentGen {
+		return c.redial(parent, gen)
+	}
+	return nil
+}
+
+// redial replaces c's connection with a new one to the optional interface
+// it was attached for, as served by the plugin parent is connected to as of
+// gen, unless another call has already done so.
+func (c *Client) redial(parent *Client, gen int) error {
+	c.redispenseMu.Lock()
+	defer c.redispenseMu.Unlock()
+
+	c.mu.RLock()
+	current, name := c.parentGen == gen, c.capability
+	c.mu.RUnlock()
+
+	if current {
+		return nil
+	}
+
+	var caps map[string]uint32
+	if err := parent.CallWith("Z_Capabilities", nil, &caps, CallOptions{Idempotent: true}); err != nil {
+		return err
+	}
+
+	id, ok := caps[name]
+	if !ok {
+		return fmt.Errorf("plugin no longer implements %s", name)
+	}
+
+	client, err := parent.Dial(id)
+	if err != nil {
+		return err
+	}
+
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+
+	c.mu.Lock()
+	c.client.Close()
+	c.broker, c.client, c.parentGen = parent.broker, client, gen
+	c.gen++
+	c.mu.Unlock()
+
+	parent.children = append(parent.children, c)
+
+	return nil
+}
@@ -0,0 +1,354 @@
+package runtime
+
+import (
+	"fmt"
+	"log"
+	"net/rpc"
+	"reflect"
+	"sync"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+)
+
+// ErrorHandler is called when an RPC call made by a generated client fails.
+// name is the qualified name of the method, i.e. "Interface.Method".
+type ErrorHandler func(name string, err error)
+
+// LogError is an ErrorHandler which logs the failed call.
+func LogError(name string, err error) {
+	log.Println("RPC call to "+name+" failed:", err.Error())
+}
+
+// FatalError is an ErrorHandler which logs the failed call, then exits.
+func FatalError(name string, err error) {
+	log.Fatalln("RPC call to "+name+" failed:", err.Error())
+}
+
+// Client holds the state shared by the methods of a generated RPC client.
+type Client struct {
+	name    string
+	onError ErrorHandler
+
+	closeOnTimeout bool
+	codec          string
+	dispatch       bool
+
+	// redispenseMu is held while redispensing, so that concurrent failed
+	// calls restart the plugin only once.
+	redispenseMu sync.Mutex
+
+	mu     sync.RWMutex
+	broker *plugin.MuxBroker
+	client *rpc.Client
+	gen    int
+	retry  *RetryPolicy
+
+	// children are the clients attached to this one, and parent, capability
+	// and parentGen describe what this one is attached to; see Attach.
+	children   []*Client
+	parent     *Client
+	capability string
+	parentGen  int
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// CloseOnTimeout makes the client close its connection when a call times
+// out, marking the plugin as unhealthy. Later calls fail with
+// rpc.ErrShutdown rather than waiting on a plugin which may be hung.
+func CloseOnTimeout(c *Client) {
+	c.closeOnTimeout = true
+}
+
+// WithCodec makes the client serve brokered interface parameters using the
+// named codec, rather than gob.
+func WithCodec(name string) ClientOption {
+	return func(c *Client) {
+		c.codec = name
+	}
+}
+
+// NewClient creates a new Client for the named interface.
+func NewClient(name string, b *plugin.MuxBroker, c *rpc.Client, onError ErrorHandler, opts ...ClientOption) *Client {
+	client := &Client{
+		name:    name,
+		broker:  b,
+		client:  c,
+		onError: onError,
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
+}
+
+// Broker returns the MuxBroker used by the client.
+func (c *Client) Broker() *plugin.MuxBroker {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.broker
+}
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+// If p is nil, calls are not retried.
+func (c *Client) SetRetryPolicy(p *RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retry = p
+}
+
+func (c *Client) conn() (*rpc.Client, *RetryPolicy, int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client, c.retry, c.gen
+}
+
+// redispense replaces the client's connection using the retry policy's
+// Redispense function, unless another call has already done so since gen.
+// Redispense may restart the plugin process, so it is called without
+// holding c.mu, leaving calls on the current connection unblocked.
+func (c *Client) redispense(p *RetryPolicy, gen int) error {
+	if p.Redispense == nil {
+		return nil
+	}
+
+	c.redispenseMu.Lock()
+	defer c.redispenseMu.Unlock()
+
+	if _, _, current := c.conn(); current != gen {
+		return nil
+	}
+
+	raw, err := p.Redispense()
+	if err != nil {
+		return err
+	}
+
+	other, ok := raw.(generatedClient)
+	if !ok {
+		return fmt.Errorf("redispensed %T is not a generated client", raw)
+	}
+
+	oc := other.Z_RuntimeClient()
+
+	var broker *plugin.MuxBroker
+	var client *rpc.Client
+	if oc != c {
+		oc.mu.RLock()
+		broker, client = oc.broker, oc.client
+		oc.mu.RUnlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if oc != c {
+		c.client.Close()
+		c.broker, c.client = broker, client
+	}
+	c.closeChildren()
+	c.gen++
+
+	return nil
+}
+
+// Call calls the named method on the plugin. If the call fails, the error is
+// passed to the client's ErrorHandler before being returned. Nil params or
+// results are sent as empty values.
+func (c *Client) Call(method string, params, results interface{}) error {
+	return c.CallWith(method, params, results, CallOptions{})
+}
+
+// CallOptions configures a single call made with CallWith.
+type CallOptions struct {
+	// Timeout is the maximum time to wait for the call to complete. If zero,
+	// the call waits forever.
+	Timeout time.Duration
+
+	// ReturnsError should be set if the caller returns an error to its own
+	// caller. Timeouts are then returned without being passed to the
+	// ErrorHandler.
+	ReturnsError bool
+
+	// Idempotent should be set if the method may safely be called more than
+	// once. Failed calls are then retried according to the client's
+	// RetryPolicy.
+	Idempotent bool
+
+	// Brokered should be set if params hold the IDs of brokered
+	// connections. Those connections do not survive redispensing the
+	// plugin, so the call is never retried, even with RetryAll.
+	Brokered bool
+}
+
+// CallWith is like Call, but with the given options. If the call times out,
+// a *TimeoutError is returned, and results are left unmodified.
+func (c *Client) CallWith(method string, params, results interface{}, opts CallOptions) error {
+	if params == nil {
+		params = new(interface{})
+	}
+
+	if results == nil {
+		results = new(interface{})
+	}
+
+	name := c.name + "." + method
+
+	if err := c.refresh(); err != nil {
+		c.onError(name, err)
+		return err
+	}
+
+	client, policy, gen := c.conn()
+	err := c.call(client, name, method, params, results, opts.Timeout)
+
+	if policy != nil {
+		for retry := 0; IsConnectionError(err); retry++ {
+			retryable := (opts.Idempotent || policy.RetryAll) && !opts.Brokered && retry+1 < policy.Attempts
+			if retryable {
+				time.Sleep(policy.backoff(retry))
+			}
+
+			// Redispense even if not retrying, so that later calls succeed.
+			rerr := c.redispense(policy, gen)
+			if !retryable {
+				break
+			}
+
+			if rerr != nil {
+				err = rerr
+				break
+			}
+
+			client, _, gen = c.conn()
+			err = c.call(client, name, method, params, results, opts.Timeout)
+		}
+	}
+
+	if err != nil {
+		if _, ok := err.(*TimeoutError); !ok || !opts.ReturnsError {
+			c.onError(name, err)
+		}
+	}
+	return err
+}
+
+func (c *Client) call(client *rpc.Client, name, method string, params, results interface{}, timeout time.Duration) error {
+	if !c.dispatches(method) {
+		return c.send(client, name, method, params, results, timeout)
+	}
+
+	body, err := Encode(params)
+	if err != nil {
+		return err
+	}
+
+	var reply []byte
+	call := &DispatchCall{Method: method, Body: body}
+	if err := c.send(client, name, "Call", call, &reply, timeout); err != nil {
+		return err
+	}
+	return Decode(reply, results)
+}
+
+// send makes a single net/rpc call to the plugin, waiting at most timeout
+// if it is positive.
+func (c *Client) send(client *rpc.Client, name, method string, params, results interface{}, timeout time.Duration) error {
+	if timeout <= 0 {
+		return client.Call("Plugin."+method, params, results)
+	}
+
+	// Decode into a copy of results, so that a reply arriving after the
+	// timeout does not race with the caller reading results.
+	reply := reflect.New(reflect.TypeOf(results).Elem())
+
+	call := client.Go("Plugin."+method, params, reply.Interface(), make(chan *rpc.Call, 1))
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-call.Done:
+		if call.Error != nil {
+			return call.Error
+		}
+		reflect.ValueOf(results).Elem().Set(reply.Elem())
+		return nil
+
+	case <-timer.C:
+		if c.closeOnTimeout {
+			client.Close()
+		}
+		return &TimeoutError{Method: name, Timeout: timeout}
+	}
+}
+
+// Go calls the named method on the plugin without waiting for a reply. If
+// the call fails, the error is passed to the client's ErrorHandler from
+// another goroutine. Nil params are sent as an empty value.
+func (c *Client) Go(method string, params interface{}) {
+	if params == nil {
+		params = new(interface{})
+	}
+
+	// The reply is decoded into a value of the type the server sends, even
+	// though it is discarded, as gob misreads the next reply on the
+	// connection after skipping an empty interface.
+	rpcMethod, reply := method, interface{}(new(interface{}))
+	if c.dispatches(method) {
+		body, err := Encode(params)
+		if err != nil {
+			go c.onError(c.name+"."+method, err)
+			return
+		}
+		rpcMethod, params = "Call", &DispatchCall{Method: method, Body: body}
+		reply = new([]byte)
+	}
+
+	if err := c.refresh(); err != nil {
+		go c.onError(c.name+"."+method, err)
+		return
+	}
+
+	client, _, _ := c.conn()
+	call := client.Go("Plugin."+rpcMethod, params, reply, make(chan *rpc.Call, 1))
+
+	go func() {
+		<-call.Done
+		if call.Error != nil {
+			c.onError(c.name+"."+method, call.Error)
+		}
+	}()
+}
+
+// Unavailable reports a call to a method which is not available over RPC,
+// passing an *UnavailableError to the client's ErrorHandler before returning
+// it.
+func (c *Client) Unavailable(method string) error {
+	name := c.name + "." + method
+	err := &UnavailableError{Method: name}
+	c.onError(name, err)
+	return err
+}
+
+// Serve serves server on a new broker connection, returning the connection's
+// ID to be passed to the plugin.
+func (c *Client) Serve(server interface{}) uint32 {
+	// Serve on the broker the call will be made with. If refreshing fails,
+	// so does the call.
+	c.refresh()
+	return Serve(c.Broker(), server, c.codec)
+}
+
+// Dial connects to the server brokered under id by the plugin, using the
+// client's codec.
+func (c *Client) Dial(id uint32) (*rpc.Client, error) {
+	if c.codec != "" {
+		return DialCodec(c.Broker(), id, c.codec)
+	}
+	return Dial(c.Broker(), id)
+}
@@ -0,0 +1,219 @@
+package runtime
+
+import (
+	"net"
+	"net/rpc"
+	"testing"
+	"time"
+)
+
+// testServer is shaped like a generated server for the gob codec.
+type testServer struct {
+	notified chan string
+	release  chan struct{}
+}
+
+func (s *testServer) Notify(params *string, _ *interface{}) error {
+	s.notified <- *params
+	return nil
+}
+
+// Block waits until s.release is closed.
+func (s *testServer) Block(_ interface{}, _ *interface{}) error {
+	<-s.release
+	return nil
+}
+
+func (s *testServer) Echo(params *string, results *string) error {
+	*results = *params
+	return nil
+}
+
+// testClient returns a Client connected to server over a pipe, using the
+// named codec, or gob if codec is empty.
+func testClient(t *testing.T, server interface{}, codec string) *Client {
+	t.Helper()
+
+	s := rpc.NewServer()
+	if err := s.RegisterName("Plugin", server); err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := net.Pipe()
+
+	var conn *rpc.Client
+	if codec != "" {
+		c, err := LookupCodec(codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go s.ServeCodec(c.NewServerCodec(b))
+		conn = rpc.NewClientWithCodec(c.NewClientCodec(a))
+	} else {
+		go s.ServeConn(b)
+		conn = rpc.NewClient(a)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	onError := func(name string, err error) {
+		t.Errorf("%s: %v", name, err)
+	}
+	return NewClient("Test", nil, conn, onError)
+}
+
+func TestGoThenCall(t *testing.T) {
+	server := &testServer{notified: make(chan string, 1)}
+	c := testClient(t, server, "")
+
+	notify := "notify"
+	c.Go("Notify", &notify)
+
+	if got := <-server.notified; got != notify {
+		t.Errorf("notified with %q; want %q", got, notify)
+	}
+
+	params, results := "echo", ""
+	if err := c.Call("Echo", &params, &results); err != nil {
+		t.Fatal(err)
+	}
+
+	if results != params {
+		t.Errorf("results = %q; want %q", results, params)
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	var handled error
+	c := NewClient("Test", nil, nil, func(name string, err error) {
+		if name != "Test.Lock" {
+			t.Errorf("ErrorHandler called for %s; want Test.Lock", name)
+		}
+		handled = err
+	})
+
+	err := c.Unavailable("Lock")
+	if _, ok := err.(*UnavailableError); !ok {
+		t.Fatalf("Unavailable() = %v; want an *UnavailableError", err)
+	}
+
+	if handled != err {
+		t.Errorf("ErrorHandler called with %v; want %v", handled, err)
+	}
+
+	if got, want := err.Error(), "Test.Lock is not available over RPC"; got != want {
+		t.Errorf("Unavailable().Error() = %q; want %q", got, want)
+	}
+}
+
+// timeoutClient returns a Client connected to a testServer whose Block
+// method waits until the test ends, and the errors passed to its
+// ErrorHandler.
+func timeoutClient(t *testing.T, opts ...ClientOption) (*Client, *[]error) {
+	t.Helper()
+
+	server := &testServer{release: make(chan struct{})}
+	t.Cleanup(func() { close(server.release) })
+
+	conn := testClient(t, server, "").client
+
+	var handled []error
+	c := NewClient("Test", nil, conn, func(name string, err error) {
+		handled = append(handled, err)
+	}, opts...)
+	return c, &handled
+}
+
+func TestTimeoutReturnsError(t *testing.T) {
+	c, handled := timeoutClient(t)
+
+	err := c.CallWith("Block", nil, nil, CallOptions{Timeout: 10 * time.Millisecond, ReturnsError: true})
+	if _, ok := err.(*TimeoutError); !ok {
+		t.Fatalf("CallWith() = %v; want a *TimeoutError", err)
+	}
+
+	if len(*handled) != 0 {
+		t.Errorf("ErrorHandler called with %v; want the error only returned", *handled)
+	}
+}
+
+func TestTimeoutHandlesError(t *testing.T) {
+	c, handled := timeoutClient(t)
+
+	err := c.CallWith("Block", nil, nil, CallOptions{Timeout: 10 * time.Millisecond})
+	if _, ok := err.(*TimeoutError); !ok {
+		t.Fatalf("CallWith() = %v; want a *TimeoutError", err)
+	}
+
+	if len(*handled) != 1 || (*handled)[0] != err {
+		t.Errorf("ErrorHandler called with %v; want %v", *handled, err)
+	}
+
+	// The connection stays open.
+	params, results := "echo", ""
+	if err := c.Call("Echo", &params, &results); err != nil {
+		t.Errorf("Call() after timeout = %v", err)
+	}
+}
+
+func TestCloseOnTimeout(t *testing.T) {
+	c, _ := timeoutClient(t, CloseOnTimeout)
+
+	err := c.CallWith("Block", nil, nil, CallOptions{Timeout: 10 * time.Millisecond, ReturnsError: true})
+	if _, ok := err.(*TimeoutError); !ok {
+		t.Fatalf("CallWith() = %v; want a *TimeoutError", err)
+	}
+
+	params, results := "echo", ""
+	if err := c.Call("Echo", &params, &results); err != rpc.ErrShutdown {
+		t.Errorf("Call() after timeout = %v; want %v", err, rpc.ErrShutdown)
+	}
+}
+
+// dispatchTestServer is shaped like a generated server with unexported wire
+// types, serving testServer's methods through Call.
+type dispatchTestServer struct {
+	testServer
+}
+
+func (s *dispatchTestServer) Call(call DispatchCall, body *[]byte) error {
+	var params string
+	if err := Decode(call.Body, &params); err != nil {
+		return err
+	}
+
+	switch call.Method {
+	case "Notify":
+		return s.Notify(&params, nil)
+	case "Echo":
+		var results string
+		if err := s.Echo(&params, &results); err != nil {
+			return err
+		}
+		var err error
+		*body, err = Encode(&results)
+		return err
+	}
+	return &UnknownMethodError{Method: "Test." + call.Method}
+}
+
+func TestDispatchGoThenCall(t *testing.T) {
+	server := &dispatchTestServer{testServer{notified: make(chan string, 1)}}
+	c := testClient(t, server, "")
+	WithDispatch(c)
+
+	notify := "notify"
+	c.Go("Notify", &notify)
+
+	if got := <-server.notified; got != notify {
+		t.Errorf("notified with %q; want %q", got, notify)
+	}
+
+	params, results := "echo", ""
+	if err := c.Call("Echo", &params, &results); err != nil {
+		t.Fatal(err)
+	}
+
+	if results != params {
+		t.Errorf("results = %q; want %q", results, params)
+	}
+}
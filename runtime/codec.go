@@ -0,0 +1,128 @@
+package runtime
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+
+	plugin "github.com/hashicorp/go-plugin"
+)
+
+// Codec creates the net/rpc codecs used to encode calls on a connection.
+type Codec interface {
+	NewClientCodec(conn io.ReadWriteCloser) rpc.ClientCodec
+	NewServerCodec(conn io.ReadWriteCloser) rpc.ServerCodec
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{
+		"json": jsonCodec{},
+	}
+)
+
+// RegisterCodec makes a codec available under name. Both sides of a
+// connection must register the same codec under the same name. The "json"
+// codec is registered by default; gob is used by go-plugin itself, and needs
+// no codec.
+func RegisterCodec(name string, c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[name] = c
+}
+
+// LookupCodec returns the codec registered under name.
+func LookupCodec(name string) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	c, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown codec %q", name)
+	}
+	return c, nil
+}
+
+// DialCodec is like Dial, but encodes calls with the named codec.
+func DialCodec(b *plugin.MuxBroker, id uint32, name string) (*rpc.Client, error) {
+	codec, err := LookupCodec(name)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := b.Dial(id)
+	if err != nil {
+		return nil, err
+	}
+	return rpc.NewClientWithCodec(codec.NewClientCodec(conn)), nil
+}
+
+// AcceptAndServeCodec accepts the connection brokered under id, then serves
+// server on it as "Plugin" using the named codec. It blocks until the
+// connection is closed.
+func AcceptAndServeCodec(b *plugin.MuxBroker, id uint32, server interface{}, name string) {
+	codec, err := LookupCodec(name)
+	if err != nil {
+		log.Printf("[ERR] plugingen: serve error: %s", err)
+		return
+	}
+
+	conn, err := b.Accept(id)
+	if err != nil {
+		log.Printf("[ERR] plugingen: accept error: %s", err)
+		return
+	}
+
+	s := rpc.NewServer()
+	if err := s.RegisterName("Plugin", server); err != nil {
+		log.Printf("[ERR] plugingen: serve error: %s", err)
+		conn.Close()
+		return
+	}
+	s.ServeCodec(codec.NewServerCodec(conn))
+}
+
+// Connect replaces c, a connection to a dispensed plugin, with a new
+// connection using the named codec. go-plugin always dispenses plugins over
+// gob, so the new connection is requested by calling the generated
+// Z_Connect method. c is closed once the new connection is established.
+func Connect(b *plugin.MuxBroker, c *rpc.Client, name string) (*rpc.Client, error) {
+	var id uint32
+	if err := c.Call("Plugin.Z_Connect", name, &id); err != nil {
+		return nil, err
+	}
+
+	client, err := DialCodec(b, id, name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Close()
+	return client, nil
+}
+
+// ServeConnect serves server on a new brokered connection using the named
+// codec, storing the connection's ID in id. It implements the server side of
+// a generated Z_Connect method.
+func ServeConnect(b *plugin.MuxBroker, server interface{}, name string, id *uint32) error {
+	if _, err := LookupCodec(name); err != nil {
+		return err
+	}
+
+	*id = b.NextId()
+	go AcceptAndServeCodec(b, *id, server, name)
+	return nil
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewClientCodec(conn io.ReadWriteCloser) rpc.ClientCodec {
+	return jsonrpc.NewClientCodec(conn)
+}
+
+func (jsonCodec) NewServerCodec(conn io.ReadWriteCloser) rpc.ServerCodec {
+	return jsonrpc.NewServerCodec(conn)
+}
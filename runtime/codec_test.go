@@ -0,0 +1,49 @@
+package runtime
+
+import "testing"
+
+// jsonTestServer is shaped like a generated server for a codec, replying
+// with an empty struct from methods without results, as net/rpc/jsonrpc
+// treats a null result as an error.
+type jsonTestServer struct {
+	notified chan string
+}
+
+func (s *jsonTestServer) Ping(_ interface{}, _ *struct{}) error {
+	return nil
+}
+
+func (s *jsonTestServer) Notify(params *string, _ *struct{}) error {
+	s.notified <- *params
+	return nil
+}
+
+func (s *jsonTestServer) Echo(params *string, results *string) error {
+	*results = *params
+	return nil
+}
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	server := &jsonTestServer{notified: make(chan string, 1)}
+	c := testClient(t, server, "json")
+
+	if err := c.Call("Ping", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	notify := "notify"
+	c.Go("Notify", &notify)
+
+	if got := <-server.notified; got != notify {
+		t.Errorf("notified with %q; want %q", got, notify)
+	}
+
+	params, results := "echo", ""
+	if err := c.Call("Echo", &params, &results); err != nil {
+		t.Fatal(err)
+	}
+
+	if results != params {
+		t.Errorf("results = %q; want %q", results, params)
+	}
+}
@@ -0,0 +1,33 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CompareResults describes the first difference between want, the results
+// of calls made to an implementation directly, and got, the results of the
+// same calls made through a plugin. It returns "" if there is none. Errors
+// are compared by message, as they are usually sent as plugin.BasicError.
+func CompareResults(want, got []interface{}) string {
+	if len(want) != len(got) {
+		return fmt.Sprintf("got %d results, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		w, g := want[i], got[i]
+
+		if we, ok := w.(error); ok {
+			if ge, ok := g.(error); !ok || ge.Error() != we.Error() {
+				return fmt.Sprintf("result %d = %#v, want error %q", i, g, we.Error())
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(w, g) {
+			return fmt.Sprintf("result %d = %#v, want %#v", i, g, w)
+		}
+	}
+
+	return ""
+}
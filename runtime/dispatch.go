@@ -0,0 +1,56 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"strings"
+)
+
+// DispatchCall is the parameter of the Call method of servers generated with
+// unexported wire types. Its body holds the gob-encoded parameters of the
+// named method, so that their types need not be exported for net/rpc.
+type DispatchCall struct {
+	Method string
+	Body   []byte
+}
+
+// WithDispatch makes the client send calls through the server's generated
+// Call method, encoding their parameters and results itself. Internal
+// methods, whose names start with Z_, are still called directly.
+func WithDispatch(c *Client) {
+	c.dispatch = true
+}
+
+// dispatches reports whether calls to method are sent through Call.
+func (c *Client) dispatches(method string) bool {
+	return c.dispatch && !strings.HasPrefix(method, "Z_")
+}
+
+// Encode encodes v for the body of a DispatchCall or its reply.
+func Encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode decodes body, as encoded by Encode, into v. An empty body, sent for
+// methods without parameters or results, leaves v unmodified.
+func Decode(body []byte, v interface{}) error {
+	if len(body) == 0 {
+		return nil
+	}
+	return gob.NewDecoder(bytes.NewReader(body)).Decode(v)
+}
+
+// UnknownMethodError is returned by a generated Call method when the named
+// method is not served.
+type UnknownMethodError struct {
+	Method string
+}
+
+func (e *UnknownMethodError) Error() string {
+	return fmt.Sprintf("unknown method %s", e.Method)
+}
@@ -0,0 +1,69 @@
+package jsonrpc2
+
+import (
+	"github.com/jakebailey/plugingen/runtime"
+)
+
+// Client holds the state shared by the methods of a generated JSON-RPC
+// client for an object.
+type Client struct {
+	name    string
+	conn    *Conn
+	object  uint64
+	onError runtime.ErrorHandler
+}
+
+// NewClient creates a new Client for the named interface, calling the
+// object with the given ID.
+func NewClient(name string, conn *Conn, object uint64, onError runtime.ErrorHandler) *Client {
+	return &Client{
+		name:    name,
+		conn:    conn,
+		object:  object,
+		onError: onError,
+	}
+}
+
+// Conn returns the connection used by the client.
+func (c *Client) Conn() *Conn {
+	return c.conn
+}
+
+// Call calls the named method, decoding its results into results. If the
+// call fails, the error is passed to the client's ErrorHandler before being
+// returned.
+func (c *Client) Call(method string, params []interface{}, results ...interface{}) error {
+	err := c.conn.Call(c.object, c.name+"."+method, params, results...)
+	if err != nil {
+		c.onError(c.name+"."+method, err)
+	}
+	return err
+}
+
+// CallError is like Call, but for methods which return an error. Errors
+// returned by the method are not passed to the ErrorHandler.
+func (c *Client) CallError(method string, params []interface{}, results ...interface{}) error {
+	err := c.conn.Call(c.object, c.name+"."+method, params, results...)
+	if err != nil && !IsApplicationError(err) {
+		c.onError(c.name+"."+method, err)
+	}
+	return err
+}
+
+// Notify calls the named method without waiting for it to complete. If the
+// call cannot be sent, the error is passed to the client's ErrorHandler.
+func (c *Client) Notify(method string, params []interface{}) {
+	if err := c.conn.Notify(c.object, c.name+"."+method, params); err != nil {
+		c.onError(c.name+"."+method, err)
+	}
+}
+
+// Unavailable reports a call to a method which is not available over RPC,
+// passing a *runtime.UnavailableError to the client's ErrorHandler before
+// returning it.
+func (c *Client) Unavailable(method string) error {
+	name := c.name + "." + method
+	err := &runtime.UnavailableError{Method: name}
+	c.onError(name, err)
+	return err
+}
@@ -0,0 +1,397 @@
+// Package jsonrpc2 implements the JSON-RPC 2.0 connection used by code
+// generated with plugingen's jsonrpc backend, for plugins written in other
+// languages.
+//
+// Messages are JSON objects separated by newlines. Both sides of a
+// connection may make calls. Calls are made to objects: the first parameter
+// of every call is the ID of the object being called, where 0 is the
+// plugin's root object and other IDs are callbacks registered by the caller
+// of a method which takes an interface parameter. Method names have the form
+// "Interface.Method". A method's results are returned as an array, except
+// for a trailing error result, which is returned as a JSON-RPC error. If the
+// method has other results, that error's data holds them as an array, so
+// that results returned alongside an error are not lost.
+package jsonrpc2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Handler handles calls to an object. method is the name of the called
+// method, without the interface name. params holds the call's parameters,
+// not including the object ID. It returns the method's results, which are
+// sent as the data of the error if one is also returned.
+type Handler func(method string, params []json.RawMessage) ([]interface{}, error)
+
+// ErrClosed is returned by calls made on, or interrupted by, a closed
+// connection.
+var ErrClosed = errors.New("jsonrpc2: connection closed")
+
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *Error           `json:"error,omitempty"`
+}
+
+// Conn is a JSON-RPC 2.0 connection.
+type Conn struct {
+	rwc io.ReadWriteCloser
+
+	encMu sync.Mutex
+	enc   *json.Encoder
+
+	mu         sync.Mutex
+	nextID     uint64
+	pending    map[uint64]chan *message
+	handlers   map[uint64]Handler
+	nextObject uint64
+	err        error
+
+	done chan struct{}
+}
+
+// NewConn creates a connection over rwc, and starts reading from it.
+func NewConn(rwc io.ReadWriteCloser) *Conn {
+	c := &Conn{
+		rwc:      rwc,
+		enc:      json.NewEncoder(rwc),
+		pending:  map[uint64]chan *message{},
+		handlers: map[uint64]Handler{},
+		done:     make(chan struct{}),
+	}
+
+	go c.read()
+
+	return c
+}
+
+type cmdConn struct {
+	io.ReadCloser
+	stdin io.WriteCloser
+	cmd   *exec.Cmd
+}
+
+func (c *cmdConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+func (c *cmdConn) Close() error {
+	c.stdin.Close()
+	err := c.cmd.Wait()
+	c.ReadCloser.Close()
+	return err
+}
+
+// Command starts cmd, and returns a connection over its stdin and stdout.
+// Closing the connection closes stdin, then waits for the process to exit.
+func Command(cmd *exec.Cmd) (*Conn, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return NewConn(&cmdConn{ReadCloser: stdout, stdin: stdin, cmd: cmd}), nil
+}
+
+// DialUnix connects to the Unix socket at path.
+func DialUnix(path string) (*Conn, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return NewConn(conn), nil
+}
+
+// Serve sets the handler for calls to the root object, for the plugin side
+// of a connection.
+func (c *Conn) Serve(h Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[0] = h
+}
+
+// Register registers a callback object, returning its ID.
+func (c *Conn) Register(h Handler) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextObject++
+	c.handlers[c.nextObject] = h
+	return c.nextObject
+}
+
+// Release unregisters the callback object with the given ID. Later calls to
+// it fail.
+func (c *Conn) Release(id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.handlers, id)
+}
+
+// Call calls method on the object with the given ID, then decodes its
+// results into results, which must be pointers. An error returned by the
+// method is returned as an *Error.
+func (c *Conn) Call(object uint64, method string, params []interface{}, results ...interface{}) error {
+	raw, err := encodeParams(object, params)
+	if err != nil {
+		return err
+	}
+
+	ch := make(chan *message, 1)
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	idRaw := json.RawMessage(fmt.Sprint(id))
+	if err := c.send(&message{ID: &idRaw, Method: method, Params: raw}); err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return err
+	}
+
+	var resp *message
+	select {
+	case resp = <-ch:
+	case <-c.done:
+		return c.Err()
+	}
+
+	if resp.Error != nil {
+		if err := unmarshalErrorResults(resp.Error, results); err != nil {
+			return fmt.Errorf("jsonrpc2: %s: invalid result: %v", method, err)
+		}
+		return resp.Error
+	}
+
+	// A missing result is allowed for methods without results.
+	var raws []json.RawMessage
+	if len(resp.Result) != 0 {
+		if err := json.Unmarshal(resp.Result, &raws); err != nil {
+			return fmt.Errorf("jsonrpc2: %s: invalid result: %v", method, err)
+		}
+	}
+
+	if err := UnmarshalParams(raws, results...); err != nil {
+		return fmt.Errorf("jsonrpc2: %s: invalid result: %v", method, err)
+	}
+
+	return nil
+}
+
+// Notify calls method on the object with the given ID without waiting for
+// it to complete.
+func (c *Conn) Notify(object uint64, method string, params []interface{}) error {
+	raw, err := encodeParams(object, params)
+	if err != nil {
+		return err
+	}
+	return c.send(&message{Method: method, Params: raw})
+}
+
+// Close closes the connection. Pending calls fail with ErrClosed.
+func (c *Conn) Close() error {
+	c.fail(ErrClosed)
+	return c.rwc.Close()
+}
+
+// Done returns a channel which is closed once the connection is closed.
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error which closed the connection, or nil if it is open.
+func (c *Conn) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *Conn) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+}
+
+func (c *Conn) send(m *message) error {
+	m.JSONRPC = "2.0"
+
+	c.encMu.Lock()
+	defer c.encMu.Unlock()
+	return c.enc.Encode(m)
+}
+
+func (c *Conn) read() {
+	dec := json.NewDecoder(c.rwc)
+
+	// Keep numbers in error data exact, so they can be decoded as results.
+	dec.UseNumber()
+
+	for {
+		m := &message{}
+		if err := dec.Decode(m); err != nil {
+			if err == io.EOF {
+				err = ErrClosed
+			}
+			c.fail(err)
+			return
+		}
+
+		if m.Method != "" {
+			go c.handle(m)
+			continue
+		}
+
+		if m.ID == nil {
+			continue
+		}
+
+		var id uint64
+		if err := json.Unmarshal(*m.ID, &id); err != nil {
+			continue
+		}
+
+		c.mu.Lock()
+		ch := c.pending[id]
+		delete(c.pending, id)
+		c.mu.Unlock()
+
+		if ch != nil {
+			ch <- m
+		}
+	}
+}
+
+func (c *Conn) handle(m *message) {
+	results, err := c.dispatch(m)
+
+	if m.ID == nil {
+		return
+	}
+
+	if results == nil {
+		results = []interface{}{}
+	}
+
+	resp := &message{ID: m.ID}
+	raw, merr := json.Marshal(results)
+	switch {
+	case merr != nil:
+		resp.Error = &Error{Code: CodeInternalError, Message: merr.Error()}
+	case err != nil:
+		resp.Error = NewError(err)
+		if len(results) != 0 {
+			e := *resp.Error
+			e.Data = json.RawMessage(raw)
+			resp.Error = &e
+		}
+	default:
+		resp.Result = raw
+	}
+
+	c.send(resp)
+}
+
+func (c *Conn) dispatch(m *message) (results []interface{}, err error) {
+	var params []json.RawMessage
+	if err := json.Unmarshal(m.Params, &params); err != nil || len(params) == 0 {
+		return nil, &Error{Code: CodeInvalidParams, Message: "params must be an array starting with an object ID"}
+	}
+
+	var object uint64
+	if err := json.Unmarshal(params[0], &object); err != nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: "invalid object ID: " + err.Error()}
+	}
+
+	c.mu.Lock()
+	h := c.handlers[object]
+	c.mu.Unlock()
+
+	if h == nil {
+		return nil, &Error{Code: CodeInvalidRequest, Message: fmt.Sprintf("no object with ID %d", object)}
+	}
+
+	method := m.Method
+	if i := strings.LastIndex(method, "."); i >= 0 {
+		method = method[i+1:]
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = &Error{Code: CodeInternalError, Message: fmt.Sprintf("panic in %s: %v", m.Method, r)}
+		}
+	}()
+
+	return h(method, params[1:])
+}
+
+// unmarshalErrorResults decodes the results sent as the data of an
+// application error into results. Data which is not an array is left alone.
+func unmarshalErrorResults(e *Error, results []interface{}) error {
+	data, ok := e.Data.([]interface{})
+	if !ok || !IsApplicationError(e) {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(raw, &raws); err != nil {
+		return err
+	}
+	return UnmarshalParams(raws, results...)
+}
+
+func encodeParams(object uint64, params []interface{}) (json.RawMessage, error) {
+	return json.Marshal(append([]interface{}{object}, params...))
+}
+
+// UnmarshalParams decodes params into ptrs, which must be pointers. It fails
+// if the number of parameters does not match.
+func UnmarshalParams(params []json.RawMessage, ptrs ...interface{}) error {
+	if len(params) != len(ptrs) {
+		return &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("got %d values, want %d", len(params), len(ptrs))}
+	}
+
+	for i, p := range params {
+		if err := json.Unmarshal(p, ptrs[i]); err != nil {
+			return &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("value %d: %v", i, err)}
+		}
+	}
+
+	return nil
+}
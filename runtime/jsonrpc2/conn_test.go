@@ -0,0 +1,98 @@
+package jsonrpc2
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestCallWireFormat(t *testing.T) {
+	a, b := net.Pipe()
+	conn := NewConn(a)
+	defer conn.Close()
+
+	// Play the part of a plugin written in another language.
+	go func() {
+		r := bufio.NewReader(b)
+
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+
+		var req struct {
+			ID     uint64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(line, &req); err != nil || req.Method != "Thinger.Sum" || string(req.Params) != "[0,[1,2,3]]" {
+			b.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"bad request: ` + string(line[:len(line)-1]) + `"}}` + "\n"))
+			return
+		}
+
+		b.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":[6]}` + "\n"))
+	}()
+
+	var sum int
+	if err := conn.Call(0, "Thinger.Sum", []interface{}{[]int{1, 2, 3}}, &sum); err != nil {
+		t.Fatal(err)
+	}
+
+	if sum != 6 {
+		t.Errorf("sum = %v; want 6", sum)
+	}
+}
+
+func TestCallback(t *testing.T) {
+	a, b := net.Pipe()
+	host, plugin := NewConn(a), NewConn(b)
+	defer host.Close()
+	defer plugin.Close()
+
+	id := host.Register(func(method string, params []json.RawMessage) ([]interface{}, error) {
+		var s string
+		if err := UnmarshalParams(params, &s); err != nil {
+			return nil, err
+		}
+		return []interface{}{method + ":" + s}, nil
+	})
+
+	var got string
+	if err := plugin.Call(id, "Replacer.Replace", []interface{}{"foo"}, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "Replace:foo"; got != want {
+		t.Errorf("callback returned %q; want %q", got, want)
+	}
+
+	host.Release(id)
+
+	err := plugin.Call(id, "Replacer.Replace", []interface{}{"foo"}, &got)
+	if err == nil || IsApplicationError(err) {
+		t.Errorf("call to released callback returned %v; want a protocol error", err)
+	}
+}
+
+func TestErrorResults(t *testing.T) {
+	a, b := net.Pipe()
+	host, plugin := NewConn(a), NewConn(b)
+	defer host.Close()
+	defer plugin.Close()
+
+	plugin.Serve(func(method string, params []json.RawMessage) ([]interface{}, error) {
+		return []interface{}{int64(1<<60 + 1)}, errors.New("EOF")
+	})
+
+	var n int64
+	err := host.Call(0, "Reader.Read", nil, &n)
+	if !IsApplicationError(err) || err.Error() != "EOF" {
+		t.Fatalf("Call() = %v; want the application error EOF", err)
+	}
+
+	if n != 1<<60+1 {
+		t.Errorf("result sent with error = %d; want %d", n, int64(1<<60+1))
+	}
+}
@@ -0,0 +1,63 @@
+package jsonrpc2
+
+import "fmt"
+
+// Error codes defined by JSON-RPC 2.0, and the code used for errors returned
+// by methods.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	CodeApplicationError = 1
+)
+
+// Error is a JSON-RPC 2.0 error object. Error values sent as parameters or
+// results are also encoded as Error objects, or null. Numbers in the Data of
+// received errors are decoded as json.Number.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Err returns e as an error, returning an untyped nil if e is nil.
+func (e *Error) Err() error {
+	if e == nil {
+		return nil
+	}
+	return e
+}
+
+// NewError converts err to an *Error with CodeApplicationError, unless it
+// already is one. A nil error is returned as nil.
+func NewError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+
+	return &Error{Code: CodeApplicationError, Message: err.Error()}
+}
+
+// IsApplicationError reports whether err was returned by the called method,
+// rather than caused by a protocol or connection failure. Codes outside the
+// range reserved by JSON-RPC 2.0 are application errors.
+func IsApplicationError(err error) bool {
+	e, ok := err.(*Error)
+	return ok && (e.Code < -32768 || e.Code > -32000)
+}
+
+// MethodNotFound returns the error for a call to an unknown method.
+func MethodNotFound(iface, method string) error {
+	return &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("%s.%s not found", iface, method)}
+}
@@ -0,0 +1,58 @@
+package runtime
+
+import "sync"
+
+// MockCall is a call recorded by a generated mock. Params holds the call's
+// parameters in order; a variadic parameter is recorded as a slice.
+type MockCall struct {
+	Method string
+	Params []interface{}
+}
+
+// MockRecorder records the calls made to a generated mock. It is safe for
+// concurrent use.
+type MockRecorder struct {
+	mu    sync.Mutex
+	calls []MockCall
+}
+
+// Record records a call to the named method.
+func (r *MockRecorder) Record(method string, params ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, MockCall{Method: method, Params: params})
+}
+
+// All returns all recorded calls, in order.
+func (r *MockRecorder) All() []MockCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]MockCall(nil), r.calls...)
+}
+
+// For returns the parameters of each recorded call to the named method, in
+// order.
+func (r *MockRecorder) For(method string) [][]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var params [][]interface{}
+	for _, call := range r.calls {
+		if call.Method == method {
+			params = append(params, call.Params)
+		}
+	}
+	return params
+}
+
+// Count returns the number of recorded calls to the named method.
+func (r *MockRecorder) Count(method string) int {
+	return len(r.For(method))
+}
+
+// Reset forgets all recorded calls.
+func (r *MockRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
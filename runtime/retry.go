@@ -0,0 +1,60 @@
+package runtime
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/rpc"
+	"time"
+)
+
+// RetryPolicy controls how generated clients retry calls to idempotent
+// methods which fail because the connection to the plugin was lost.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts made, including the first.
+	Attempts int
+
+	// RetryAll retries calls to all methods, not just idempotent ones,
+	// except those passing brokered interfaces.
+	RetryAll bool
+
+	// Backoff is the delay before the first retry. It doubles after each
+	// retry, up to MaxBackoff if set.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// Redispense, if set, is called after a call fails to obtain a new
+	// connection to the plugin, for example by dispensing it again from a
+	// plugin.Client which has restarted the plugin process. It is called
+	// even if the call is not retried, so that later calls may succeed. It
+	// must return a generated RPC client for the same interface.
+	Redispense func() (interface{}, error)
+}
+
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.Backoff
+	for i := 0; i < retry; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return d
+}
+
+// generatedClient is implemented by all generated RPC clients.
+type generatedClient interface {
+	Z_RuntimeClient() *Client
+}
+
+// IsConnectionError reports whether err was caused by a problem with the
+// connection to the plugin, such as rpc.ErrShutdown or io.EOF, rather than
+// being returned by the plugin itself or by encoding the call.
+func IsConnectionError(err error) bool {
+	if errors.Is(err, rpc.ErrShutdown) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,129 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"testing"
+	"time"
+)
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{rpc.ErrShutdown, true},
+		{io.EOF, true},
+		{io.ErrUnexpectedEOF, true},
+		{fmt.Errorf("reading: %w", io.EOF), true},
+		{&net.OpError{Op: "read", Err: errors.New("connection reset")}, true},
+		{rpc.ServerError("failed"), false},
+		{&TimeoutError{Method: "Test.Echo"}, false},
+		{errors.New("gob: type not registered for interface: main.T"), false},
+		{&UnknownMethodError{Method: "Test.Echo"}, false},
+	}
+
+	for _, test := range tests {
+		if got := IsConnectionError(test.err); got != test.want {
+			t.Errorf("IsConnectionError(%v) = %v; want %v", test.err, got, test.want)
+		}
+	}
+}
+
+type redispensed struct {
+	c *Client
+}
+
+func (r redispensed) Z_RuntimeClient() *Client {
+	return r.c
+}
+
+func TestRetryBrokered(t *testing.T) {
+	server := &testServer{notified: make(chan string, 1)}
+
+	// A client whose connection has been lost.
+	lost := testClient(t, server, "")
+	lost.client.Close()
+
+	var failed []string
+	c := NewClient("Test", nil, lost.client, func(name string, err error) {
+		failed = append(failed, name)
+	})
+	c.SetRetryPolicy(&RetryPolicy{
+		Attempts: 2,
+		RetryAll: true,
+		Redispense: func() (interface{}, error) {
+			return redispensed{testClient(t, server, "")}, nil
+		},
+	})
+
+	params, results := "echo", ""
+	if err := c.CallWith("Echo", &params, &results, CallOptions{Brokered: true}); !IsConnectionError(err) {
+		t.Fatalf("brokered call returned %v; want a connection error", err)
+	}
+
+	if len(failed) != 1 {
+		t.Errorf("ErrorHandler called for %v; want one call", failed)
+	}
+
+	// The failed call redispensed the plugin, so later calls succeed.
+	if err := c.CallWith("Echo", &params, &results, CallOptions{Brokered: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if results != params {
+		t.Errorf("results = %q; want %q", results, params)
+	}
+}
+
+func TestRedispenseDoesNotBlockCalls(t *testing.T) {
+	server := &testServer{notified: make(chan string, 1)}
+	c := testClient(t, server, "")
+
+	started, release := make(chan struct{}), make(chan struct{})
+	policy := &RetryPolicy{
+		Redispense: func() (interface{}, error) {
+			close(started)
+			<-release
+			return redispensed{testClient(t, server, "")}, nil
+		},
+	}
+
+	done := make(chan error)
+	go func() { done <- c.redispense(policy, 0) }()
+	<-started
+
+	// Calls on the current connection proceed while the plugin restarts.
+	called := make(chan error)
+	go func() {
+		params, results := "echo", ""
+		called <- c.Call("Echo", &params, &results)
+	}()
+
+	select {
+	case err := <-called:
+		if err != nil {
+			t.Errorf("Call() during redispense = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Call() blocked while redispensing")
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	// A second failed call from the same generation does not redispense
+	// again.
+	if err := c.redispense(policy, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, gen := c.conn(); gen != 1 {
+		t.Errorf("gen = %d after redispensing once; want 1", gen)
+	}
+}
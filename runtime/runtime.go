@@ -0,0 +1,65 @@
+// Package runtime contains support code for plugins generated by plugingen.
+//
+// Generated code calls into this package for call dispatch, brokering, and
+// error handling, rather than inlining those pieces into every method.
+package runtime
+
+import (
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+)
+
+func init() {
+	gob.Register(&plugin.BasicError{})
+}
+
+// WrapError wraps err in a plugin.BasicError so that it can be sent over
+// net/rpc. A nil error is returned as an untyped nil.
+func WrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return plugin.NewBasicError(err)
+}
+
+// ToBasicError converts err to a *plugin.BasicError, for codecs which cannot
+// encode interface values. A nil error is returned as nil.
+func ToBasicError(err error) *plugin.BasicError {
+	if err == nil {
+		return nil
+	}
+	return plugin.NewBasicError(err)
+}
+
+// FromBasicError converts err back to an error, returning an untyped nil if
+// err is nil.
+func FromBasicError(err *plugin.BasicError) error {
+	if err == nil {
+		return nil
+	}
+	return err
+}
+
+// UnavailableError is returned by generated client methods which were
+// skipped with a //plugingen:skip directive.
+type UnavailableError struct {
+	Method string
+}
+
+func (e *UnavailableError) Error() string {
+	return e.Method + " is not available over RPC"
+}
+
+// TimeoutError is returned by generated client methods when a call does not
+// complete within its timeout.
+type TimeoutError struct {
+	Method  string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %v", e.Method, e.Timeout)
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"fmt"
+	"net/rpc"
+
+	plugin "github.com/hashicorp/go-plugin"
+)
+
+// Dial connects to the server brokered under id, as returned by Client.Serve
+// on the other side of the connection.
+func Dial(b *plugin.MuxBroker, id uint32) (*rpc.Client, error) {
+	conn, err := b.Dial(id)
+	if err != nil {
+		return nil, err
+	}
+	return rpc.NewClient(conn), nil
+}
+
+// Serve serves server on a new connection brokered by b, returning the
+// connection's ID to be passed to the other side. The connection uses the
+// named codec, or gob if codec is empty.
+func Serve(b *plugin.MuxBroker, server interface{}, codec string) uint32 {
+	id := b.NextId()
+	if codec != "" {
+		go AcceptAndServeCodec(b, id, server, codec)
+	} else {
+		go b.AcceptAndServe(id, server)
+	}
+	return id
+}
+
+// PanicError is returned to the caller of an RPC method when the
+// implementation panics.
+type PanicError struct {
+	Method string
+	Value  interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in %s: %v", e.Method, e.Value)
+}
+
+// Recover recovers from a panic in a generated server method, storing it as
+// a *PanicError in err. It must be deferred directly by the method.
+func Recover(name string, err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Method: name, Value: r}
+	}
+}
@@ -0,0 +1,93 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+
+	plugin "github.com/hashicorp/go-plugin"
+)
+
+// Supervisor manages a plugin process, starting it again if it exits or
+// stops responding.
+type Supervisor struct {
+	name    string
+	factory func() *plugin.ClientConfig
+
+	mu     sync.Mutex
+	client *plugin.Client
+}
+
+// NewSupervisor creates a Supervisor which dispenses the named plugin from
+// processes configured by factory. factory is called each time the process
+// is started, as a plugin.ClientConfig cannot be reused. No process is
+// started until Dispense is called.
+func NewSupervisor(name string, factory func() *plugin.ClientConfig) *Supervisor {
+	return &Supervisor{
+		name:    name,
+		factory: factory,
+	}
+}
+
+// Dispense dispenses the plugin, first starting the plugin process if it
+// isn't running or is not responding. It is suitable for use as
+// RetryPolicy.Redispense.
+func (s *Supervisor) Dispense() (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client != nil && s.client.Exited() {
+		s.client.Kill()
+		s.client = nil
+	}
+
+	if s.client != nil {
+		if protocol, err := s.client.Client(); err != nil || protocol.Ping() != nil {
+			s.client.Kill()
+			s.client = nil
+		}
+	}
+
+	if s.client == nil {
+		s.client = plugin.NewClient(s.factory())
+	}
+
+	protocol, err := s.client.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	return protocol.Dispense(s.name)
+}
+
+// Start dispenses the plugin, then sets policy as the retry policy of the
+// dispensed client, with its Redispense hook set to the supervisor's
+// Dispense method. The plugin process is stopped if an error occurs.
+func (s *Supervisor) Start(policy RetryPolicy) (interface{}, error) {
+	raw, err := s.Dispense()
+	if err != nil {
+		s.Kill()
+		return nil, err
+	}
+
+	client, ok := raw.(generatedClient)
+	if !ok {
+		s.Kill()
+		return nil, fmt.Errorf("dispensed %T is not a generated client", raw)
+	}
+
+	policy.Redispense = s.Dispense
+	client.Z_RuntimeClient().SetRetryPolicy(&policy)
+
+	return raw, nil
+}
+
+// Kill stops the plugin process, if running.
+func (s *Supervisor) Kill() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client != nil {
+		s.client.Kill()
+		s.client = nil
+	}
+}
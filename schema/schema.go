@@ -0,0 +1,365 @@
+// Package schema describes analyzed interfaces in a machine-readable form,
+// so that plugins can be implemented, validated and documented by tools
+// other than plugingen.
+//
+// Two protocols are described. For "netrpc", calls are made by go-plugin
+// over net/rpc to methods named "Plugin.Method", with parameters and results
+// sent as gob-encoded structs whose field names are given by Var.Field; the
+// schema includes go-plugin's handshake. If Codec is set, the host instead
+// calls "Plugin.Z_Connect" with the codec's name, then makes its calls on
+// the brokered connection whose ID is returned, encoded with the codec, as
+// are callbacks; methods without results reply with an empty object. For
+// "jsonrpc2", calls are made as described by the runtime/jsonrpc2 package.
+//
+// Types are described as sent by the encoding named by Schema.Encoding. For
+// "gob", used by netrpc without a codec, struct fields are named as in Go,
+// embedded structs are sent as fields named after their type, struct tags
+// are ignored, and fields holding zero values may be omitted. For "json",
+// fields are named and omitted as by encoding/json, whose rules are also
+// assumed for codecs other than "json".
+//
+// Types are described by kind:
+//
+//	boolean, integer, number, string   scalars
+//	bytes                              a byte string, sent as base64 in JSON
+//	array                              an array of Elem
+//	map                                a map with keys of Key and values of Elem
+//	object                             a struct with the given Fields
+//	ref                                the object type named Name in Schema.Types
+//	any                                any value
+//	error                              an error object with a message, or null
+//	callback                           an object implementing the interface named Name; for
+//	                                   netrpc, the MuxBroker ID of its connection, and for
+//	                                   jsonrpc2, its object ID
+//	unsupported                        a value which cannot be sent
+package schema
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/jakebailey/plugingen/analyzer"
+	"github.com/jakebailey/plugingen/typesext"
+)
+
+// Schema describes a set of interfaces.
+type Schema struct {
+	Protocol   string           `json:"protocol"`
+	Codec      string           `json:"codec,omitempty"`
+	Encoding   string           `json:"encoding"`
+	Handshake  *Handshake       `json:"handshake,omitempty"`
+	Interfaces []*Interface     `json:"interfaces"`
+	Types      map[string]*Type `json:"types,omitempty"`
+}
+
+// Handshake describes go-plugin's handshake, which a plugin process must
+// check before serving.
+type Handshake struct {
+	ProtocolVersion  uint   `json:"protocolVersion"`
+	MagicCookieKey   string `json:"magicCookieKey"`
+	MagicCookieValue string `json:"magicCookieValue"`
+}
+
+// Interface describes an interface. Root interfaces are implemented by the
+// plugin itself; others are passed as callbacks.
+type Interface struct {
+	Name    string    `json:"name"`
+	Root    bool      `json:"root,omitempty"`
+	Methods []*Method `json:"methods"`
+}
+
+// Method describes a method. Method is the name it is called by. For
+// jsonrpc2, Error is set if the method's last result is an error, which is
+// not listed in Results; when it is returned, Results are sent as the
+// error's data. Skipped methods are not described.
+type Method struct {
+	Name     string `json:"name"`
+	Method   string `json:"method"`
+	Params   []*Var `json:"params"`
+	Results  []*Var `json:"results"`
+	Error    bool   `json:"error,omitempty"`
+	Variadic bool   `json:"variadic,omitempty"`
+	Oneway   bool   `json:"oneway,omitempty"`
+	Retain   bool   `json:"retain,omitempty"`
+}
+
+// Var describes a parameter or result. Name is its name in the Go source,
+// if any, and Field is its field in the params or results struct.
+type Var struct {
+	Name  string `json:"name,omitempty"`
+	Field string `json:"field,omitempty"`
+	Type  *Type  `json:"type"`
+}
+
+// Type describes the encoding of a type.
+type Type struct {
+	Kind     string   `json:"kind"`
+	Name     string   `json:"name,omitempty"`
+	Elem     *Type    `json:"elem,omitempty"`
+	Key      *Type    `json:"key,omitempty"`
+	Len      int64    `json:"len,omitempty"`
+	Fields   []*Field `json:"fields,omitempty"`
+	Nullable bool     `json:"nullable,omitempty"`
+}
+
+// Field describes a field of an object type.
+type Field struct {
+	Name     string `json:"name"`
+	Type     *Type  `json:"type"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+type builder struct {
+	netrpc bool
+	gob    bool
+	name   func(*analyzer.Interface) string
+	field  func(*analyzer.Method, int) string
+	schema *Schema
+}
+
+// Build describes ifaces for the given protocol and, for netrpc, codec, where
+// an empty codec means gob. name returns the name used for an interface, and
+// field the name of the field holding a parameter of a method over net/rpc,
+// which should match the generated code.
+func Build(protocol, codec string, ifaces []*analyzer.Interface, name func(*analyzer.Interface) string, field func(*analyzer.Method, int) string) *Schema {
+	b := &builder{
+		netrpc: protocol == "netrpc",
+		gob:    protocol == "netrpc" && codec == "",
+		name:   name,
+		field:  field,
+		schema: &Schema{
+			Protocol:   protocol,
+			Codec:      codec,
+			Encoding:   "json",
+			Interfaces: []*Interface{},
+			Types:      map[string]*Type{},
+		},
+	}
+
+	if b.gob {
+		b.schema.Encoding = "gob"
+	}
+
+	for _, iface := range ifaces {
+		b.schema.Interfaces = append(b.schema.Interfaces, b.iface(iface))
+	}
+
+	return b.schema
+}
+
+func (b *builder) iface(iface *analyzer.Interface) *Interface {
+	name := b.name(iface)
+
+	s := &Interface{
+		Name:    name,
+		Root:    iface.TopLevel,
+		Methods: []*Method{},
+	}
+
+	for _, m := range iface.Methods {
+		if m.Skip {
+			continue
+		}
+
+		sm := &Method{
+			Name:     m.Name,
+			Method:   name + "." + m.Name,
+			Params:   []*Var{},
+			Results:  []*Var{},
+			Variadic: m.Variadic,
+			Oneway:   m.Oneway,
+			Retain:   m.Retain,
+		}
+
+		if b.netrpc {
+			sm.Method = "Plugin." + m.Name
+		}
+
+		for i, p := range m.Params {
+			v := b.v(p)
+			if b.netrpc {
+				v.Field = b.field(m, i)
+				if p.IFace != nil {
+					v.Field += "ID"
+				}
+			}
+			sm.Params = append(sm.Params, v)
+		}
+
+		results := m.Results
+		if n := len(results); !b.netrpc && n != 0 && typesext.IsError(results[n-1].Typ) {
+			sm.Error = true
+			results = results[:n-1]
+		}
+
+		for i, r := range results {
+			v := b.v(r)
+			if b.netrpc {
+				v.Field = fmt.Sprintf("R%d", i)
+			}
+			sm.Results = append(sm.Results, v)
+		}
+
+		s.Methods = append(s.Methods, sm)
+	}
+
+	return s
+}
+
+func (b *builder) v(v *analyzer.Var) *Var {
+	if v.IFace != nil {
+		return &Var{Name: v.Name, Type: &Type{Kind: "callback", Name: b.name(v.IFace)}}
+	}
+	return &Var{Name: v.Name, Type: b.typ(v.Typ)}
+}
+
+func (b *builder) typ(t types.Type) *Type {
+	t = types.Unalias(t)
+
+	if typesext.IsError(t) {
+		return &Type{Kind: "error", Nullable: true}
+	}
+
+	if types.IsInterface(t) {
+		return &Type{Kind: "any", Nullable: true}
+	}
+
+	switch t := t.(type) {
+	case *types.Named:
+		name := types.TypeString(t, func(pkg *types.Package) string { return pkg.Name() })
+
+		if _, ok := t.Underlying().(*types.Struct); ok {
+			if _, ok := b.schema.Types[name]; !ok {
+				// Reserve the name first, in case the type refers to itself.
+				b.schema.Types[name] = nil
+				b.schema.Types[name] = b.typ(t.Underlying())
+			}
+			return &Type{Kind: "ref", Name: name}
+		}
+
+		typ := *b.typ(t.Underlying())
+		typ.Name = name
+		return &typ
+
+	case *types.Basic:
+		info := t.Info()
+		switch {
+		case info&types.IsBoolean != 0:
+			return &Type{Kind: "boolean"}
+		case info&types.IsInteger != 0:
+			return &Type{Kind: "integer"}
+		case info&types.IsFloat != 0:
+			return &Type{Kind: "number"}
+		case info&types.IsString != 0:
+			return &Type{Kind: "string"}
+		}
+
+	case *types.Slice:
+		if isByte(t.Elem()) {
+			return &Type{Kind: "bytes", Nullable: true}
+		}
+		return &Type{Kind: "array", Elem: b.typ(t.Elem()), Nullable: true}
+
+	case *types.Array:
+		return &Type{Kind: "array", Elem: b.typ(t.Elem()), Len: t.Len()}
+
+	case *types.Map:
+		return &Type{Kind: "map", Key: b.typ(t.Key()), Elem: b.typ(t.Elem()), Nullable: true}
+
+	case *types.Pointer:
+		typ := *b.typ(t.Elem())
+		typ.Nullable = true
+		return &typ
+
+	case *types.Struct:
+		if b.gob {
+			return &Type{Kind: "object", Fields: b.gobFields(t)}
+		}
+		return &Type{Kind: "object", Fields: b.fields(t)}
+	}
+
+	return &Type{Kind: "unsupported"}
+}
+
+// fields describes the fields of a struct as encoded by encoding/json.
+// Untagged embedded structs are flattened.
+func (b *builder) fields(t *types.Struct) []*Field {
+	fields := []*Field{}
+
+	for i := 0; i < t.NumFields(); i++ {
+		f := t.Field(i)
+		name, opts := jsonTag(t.Tag(i))
+
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		if f.Anonymous() && name == "" {
+			ft := f.Type()
+			if p, ok := ft.(*types.Pointer); ok {
+				ft = p.Elem()
+			}
+			if st, ok := ft.Underlying().(*types.Struct); ok {
+				fields = append(fields, b.fields(st)...)
+				continue
+			}
+		}
+
+		if !f.Exported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name()
+		}
+
+		fields = append(fields, &Field{
+			Name:     name,
+			Type:     b.typ(f.Type()),
+			Optional: strings.Contains(","+opts+",", ",omitempty,"),
+		})
+	}
+
+	return fields
+}
+
+// gobFields describes the fields of a struct as encoded by encoding/gob,
+// which sends exported fields by their Go names, including embedded structs,
+// and ignores channels and functions.
+func (b *builder) gobFields(t *types.Struct) []*Field {
+	fields := []*Field{}
+
+	for i := 0; i < t.NumFields(); i++ {
+		f := t.Field(i)
+		if !f.Exported() {
+			continue
+		}
+
+		switch f.Type().Underlying().(type) {
+		case *types.Chan, *types.Signature:
+			continue
+		}
+
+		fields = append(fields, &Field{
+			Name: f.Name(),
+			Type: b.typ(f.Type()),
+		})
+	}
+
+	return fields
+}
+
+func jsonTag(tag string) (name, opts string) {
+	v := reflect.StructTag(tag).Get("json")
+	if i := strings.Index(v, ","); i >= 0 {
+		return v[:i], v[i+1:]
+	}
+	return v, ""
+}
+
+func isByte(t types.Type) bool {
+	basic, ok := t.(*types.Basic)
+	return ok && basic.Kind() == types.Byte
+}
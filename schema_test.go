@@ -0,0 +1,97 @@
+package plugingen
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaOptions(t *testing.T) {
+	config := Config{
+		Types:      []string{"Store"},
+		Args:       []string{"./testdata/golden/unexported"},
+		Unexported: true,
+	}
+
+	if _, _, err := Schema(context.Background(), config); err != ErrSchemaUnexported {
+		t.Errorf("Schema() with Unexported = %v; want %v", err, ErrSchemaUnexported)
+	}
+
+	config.Unexported = false
+	config.Codec = "json"
+	config.Embedded = true
+
+	sch, _, err := Schema(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sch.Codec != "json" {
+		t.Errorf("schema codec = %q; want json", sch.Codec)
+	}
+
+	// io.Closer is only embedded, so is served as part of Store.
+	var names []string
+	for _, iface := range sch.Interfaces {
+		names = append(names, iface.Name)
+	}
+	if got, want := strings.Join(names, ","), "Flusher,Store,Visitor"; got != want {
+		t.Errorf("schema interfaces = %s; want %s", got, want)
+	}
+
+	// The handshake matches the code generated with the same options.
+	config.Unexported = true
+	config.Codec = ""
+	config.SubPkg = "plug"
+	files, _, err := Generate(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cookie := `MagicCookieValue: "` + sch.Handshake.MagicCookieValue + `"`
+	if !strings.Contains(string(files[filepath.Join("testdata", "golden", "unexported", "plug", "plugingen.go")]), cookie) {
+		t.Errorf("generated code does not contain %s", cookie)
+	}
+}
+
+func TestSchemaEncoding(t *testing.T) {
+	tests := []struct {
+		codec    string
+		encoding string
+		fields   string
+	}{
+		{"", "gob", "Name,Tags"},
+		{"json", "json", "name,tags"},
+	}
+
+	for _, test := range tests {
+		config := Config{
+			Types: []string{"Tree"},
+			Args:  []string{"./testdata/golden/aliases"},
+			Codec: test.codec,
+		}
+
+		sch, _, err := Schema(context.Background(), config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if sch.Encoding != test.encoding {
+			t.Errorf("codec %q: schema encoding = %q; want %q", test.codec, sch.Encoding, test.encoding)
+		}
+
+		var names []string
+		for _, m := range sch.Interfaces[0].Methods {
+			if m.Name != "Describe" {
+				continue
+			}
+			for _, f := range m.Results[0].Type.Fields {
+				names = append(names, f.Name)
+			}
+		}
+		if got := strings.Join(names, ","); got != test.fields {
+			t.Errorf("codec %q: Describe result fields = %s; want %s", test.codec, got, test.fields)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package aliases
+
+import "io"
+
+type ID = string
+
+type Reader = io.Reader
+
+type Meta = struct {
+	Name string   `json:"name" xml:"n,attr"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+type Node struct {
+	Children []*Node
+}
+
+type Tree interface {
+	Lookup(id ID) (Node, bool)
+	Load(r Reader) error
+	Describe(opts struct {
+		Depth int `json:"depth"`
+	}) Meta
+	Pairs() map[ID][]struct{ Key, Value ID }
+}
@@ -0,0 +1,309 @@
+// Code generated by "plugingen -type=Tree"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	aliases "github.com/jakebailey/plugingen/testdata/golden/aliases"
+	"io"
+	"net/rpc"
+)
+
+// TreePlugin implements the Plugin interface for Tree.
+type TreePlugin struct {
+	impl aliases.Tree
+}
+
+func NewTreePlugin(impl aliases.Tree) *TreePlugin {
+	return &TreePlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*TreePlugin)(nil) // Compile-time check that TreePlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *TreePlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewTreeRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *TreePlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewTreeRPCClient(b, c), nil
+}
+
+// TreeRPCClient implements Tree via net/rpc.
+type TreeRPCClient struct {
+	client *runtime.Client
+}
+
+func NewTreeRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *TreeRPCClient {
+	return &TreeRPCClient{client: runtime.NewClient("Tree", b, c, runtime.LogError)}
+}
+
+var _ aliases.Tree = (*TreeRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *TreeRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *TreeRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// TreeRPCServer implements the net/rpc server for Tree.
+type TreeRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   aliases.Tree
+}
+
+func NewTreeRPCServer(b *goplugin.MuxBroker, impl aliases.Tree) *TreeRPCServer {
+	return &TreeRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Tree_DescribeParams contains parameters for the Describe function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Tree_DescribeParams struct {
+	P0 struct {
+		Depth int `json:"depth"`
+	}
+}
+
+// Z_Tree_DescribeResults contains results for the Describe function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Tree_DescribeResults struct {
+	R0 aliases.Meta
+}
+
+// Describe implements Describe for the Tree interface.
+func (c *TreeRPCClient) Describe(opts struct {
+	Depth int `json:"depth"`
+}) aliases.Meta {
+	params := &Z_Tree_DescribeParams{P0: opts}
+	results := &Z_Tree_DescribeResults{}
+
+	c.client.Call("Describe", params, results)
+
+	return results.R0
+}
+
+// Describe implements the server side of net/rpc calls to Describe.
+func (s *TreeRPCServer) Describe(params *Z_Tree_DescribeParams, results *Z_Tree_DescribeResults) (err error) {
+	defer runtime.Recover("Tree.Describe", &err)
+
+	r0 := s.impl.Describe(params.P0)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_Tree_LoadParams contains parameters for the Load function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Tree_LoadParams struct {
+	P0ID uint32
+}
+
+// Z_Tree_LoadResults contains results for the Load function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Tree_LoadResults struct {
+	R0 error
+}
+
+// Load implements Load for the Tree interface.
+func (c *TreeRPCClient) Load(r aliases.Reader) error {
+	params := &Z_Tree_LoadParams{P0ID: c.client.Serve(NewReaderRPCServer(c.client.Broker(), r))}
+	results := &Z_Tree_LoadResults{}
+
+	c.client.CallWith("Load", params, results, runtime.CallOptions{Brokered: true})
+
+	return results.R0
+}
+
+// Load implements the server side of net/rpc calls to Load.
+func (s *TreeRPCServer) Load(params *Z_Tree_LoadParams, results *Z_Tree_LoadResults) (err error) {
+	defer runtime.Recover("Tree.Load", &err)
+
+	rrpc, err := runtime.Dial(s.broker, params.P0ID)
+	if err != nil {
+		return err
+	}
+	defer rrpc.Close()
+	rclient := NewReaderRPCClient(s.broker, rrpc)
+
+	r0 := s.impl.Load(rclient)
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+
+// Z_Tree_LookupParams contains parameters for the Lookup function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Tree_LookupParams struct {
+	P0 aliases.ID
+}
+
+// Z_Tree_LookupResults contains results for the Lookup function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Tree_LookupResults struct {
+	R0 aliases.Node
+	R1 bool
+}
+
+// Lookup implements Lookup for the Tree interface.
+func (c *TreeRPCClient) Lookup(p0 aliases.ID) (aliases.Node, bool) {
+	params := &Z_Tree_LookupParams{P0: p0}
+	results := &Z_Tree_LookupResults{}
+
+	c.client.Call("Lookup", params, results)
+
+	return results.R0, results.R1
+}
+
+// Lookup implements the server side of net/rpc calls to Lookup.
+func (s *TreeRPCServer) Lookup(params *Z_Tree_LookupParams, results *Z_Tree_LookupResults) (err error) {
+	defer runtime.Recover("Tree.Lookup", &err)
+
+	r0, r1 := s.impl.Lookup(params.P0)
+
+	results.R0 = r0
+	results.R1 = r1
+
+	return nil
+}
+
+// Z_Tree_PairsResults contains results for the Pairs function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Tree_PairsResults struct {
+	R0 map[aliases.ID][]struct {
+		Key   aliases.ID
+		Value aliases.ID
+	}
+}
+
+// Pairs implements Pairs for the Tree interface.
+func (c *TreeRPCClient) Pairs() map[aliases.ID][]struct {
+	Key   aliases.ID
+	Value aliases.ID
+} {
+	results := &Z_Tree_PairsResults{}
+
+	c.client.Call("Pairs", nil, results)
+
+	return results.R0
+}
+
+// Pairs implements the server side of net/rpc calls to Pairs.
+func (s *TreeRPCServer) Pairs(_ interface{}, results *Z_Tree_PairsResults) (err error) {
+	defer runtime.Recover("Tree.Pairs", &err)
+
+	r0 := s.impl.Pairs()
+
+	results.R0 = r0
+
+	return nil
+}
+
+// ReaderPlugin implements the Plugin interface for Reader.
+type ReaderPlugin struct {
+	impl io.Reader
+}
+
+func NewReaderPlugin(impl io.Reader) *ReaderPlugin {
+	return &ReaderPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*ReaderPlugin)(nil) // Compile-time check that ReaderPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *ReaderPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewReaderRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *ReaderPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewReaderRPCClient(b, c), nil
+}
+
+// ReaderRPCClient implements Reader via net/rpc.
+type ReaderRPCClient struct {
+	client *runtime.Client
+}
+
+func NewReaderRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *ReaderRPCClient {
+	return &ReaderRPCClient{client: runtime.NewClient("Reader", b, c, runtime.LogError)}
+}
+
+var _ io.Reader = (*ReaderRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *ReaderRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *ReaderRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// ReaderRPCServer implements the net/rpc server for Reader.
+type ReaderRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   io.Reader
+}
+
+func NewReaderRPCServer(b *goplugin.MuxBroker, impl io.Reader) *ReaderRPCServer {
+	return &ReaderRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Reader_ReadParams contains parameters for the Read function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Reader_ReadParams struct {
+	P0 []byte
+}
+
+// Z_Reader_ReadResults contains results for the Read function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Reader_ReadResults struct {
+	R0 int
+	R1 error
+}
+
+// Read implements Read for the Reader interface.
+func (c *ReaderRPCClient) Read(p []byte) (int, error) {
+	params := &Z_Reader_ReadParams{P0: p}
+	results := &Z_Reader_ReadResults{}
+
+	c.client.Call("Read", params, results)
+
+	return results.R0, results.R1
+}
+
+// Read implements the server side of net/rpc calls to Read.
+func (s *ReaderRPCServer) Read(params *Z_Reader_ReadParams, results *Z_Reader_ReadResults) (err error) {
+	defer runtime.Recover("Reader.Read", &err)
+
+	r0, r1 := s.impl.Read(params.P0)
+
+	results.R0 = r0
+	results.R1 = runtime.WrapError(r1)
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "06ab9b462701d11bd27ca1c35963a584",
+	ProtocolVersion:  1,
+}
@@ -0,0 +1,167 @@
+// Code generated by "plugingen -type=Checker"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	allowerror "github.com/jakebailey/plugingen/testdata/golden/allowerror"
+	"net/rpc"
+)
+
+// CheckerPlugin implements the Plugin interface for Checker.
+type CheckerPlugin struct {
+	impl allowerror.Checker
+}
+
+func NewCheckerPlugin(impl allowerror.Checker) *CheckerPlugin {
+	return &CheckerPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*CheckerPlugin)(nil) // Compile-time check that CheckerPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *CheckerPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewCheckerRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *CheckerPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewCheckerRPCClient(b, c), nil
+}
+
+// CheckerRPCClient implements Checker via net/rpc.
+type CheckerRPCClient struct {
+	client *runtime.Client
+}
+
+func NewCheckerRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *CheckerRPCClient {
+	return &CheckerRPCClient{client: runtime.NewClient("Checker", b, c, runtime.LogError)}
+}
+
+var _ allowerror.Checker = (*CheckerRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *CheckerRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *CheckerRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// CheckerRPCServer implements the net/rpc server for Checker.
+type CheckerRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   allowerror.Checker
+}
+
+func NewCheckerRPCServer(b *goplugin.MuxBroker, impl allowerror.Checker) *CheckerRPCServer {
+	return &CheckerRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Checker_CheckParams contains parameters for the Check function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Checker_CheckParams struct {
+	P0 error
+}
+
+// Z_Checker_CheckResults contains results for the Check function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Checker_CheckResults struct {
+	R0 error
+}
+
+// Check implements Check for the Checker interface.
+func (c *CheckerRPCClient) Check(p0 error) error {
+	params := &Z_Checker_CheckParams{P0: p0}
+	results := &Z_Checker_CheckResults{}
+
+	c.client.Call("Check", params, results)
+
+	return results.R0
+}
+
+// Check implements the server side of net/rpc calls to Check.
+func (s *CheckerRPCServer) Check(params *Z_Checker_CheckParams, results *Z_Checker_CheckResults) (err error) {
+	defer runtime.Recover("Checker.Check", &err)
+
+	r0 := s.impl.Check(params.P0)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_Checker_ErrorsResults contains results for the Errors function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Checker_ErrorsResults struct {
+	R0 []error
+}
+
+// Errors implements Errors for the Checker interface.
+func (c *CheckerRPCClient) Errors() []error {
+	results := &Z_Checker_ErrorsResults{}
+
+	c.client.Call("Errors", nil, results)
+
+	return results.R0
+}
+
+// Errors implements the server side of net/rpc calls to Errors.
+func (s *CheckerRPCServer) Errors(_ interface{}, results *Z_Checker_ErrorsResults) (err error) {
+	defer runtime.Recover("Checker.Errors", &err)
+
+	r0 := s.impl.Errors()
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_Checker_ValidateParams contains parameters for the Validate function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Checker_ValidateParams struct {
+	P0 string
+}
+
+// Z_Checker_ValidateResults contains results for the Validate function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Checker_ValidateResults struct {
+	R0 bool
+	R1 error
+}
+
+// Validate implements Validate for the Checker interface.
+func (c *CheckerRPCClient) Validate(p0 string) (bool, error) {
+	params := &Z_Checker_ValidateParams{P0: p0}
+	results := &Z_Checker_ValidateResults{}
+
+	c.client.Call("Validate", params, results)
+
+	return results.R0, results.R1
+}
+
+// Validate implements the server side of net/rpc calls to Validate.
+func (s *CheckerRPCServer) Validate(params *Z_Checker_ValidateParams, results *Z_Checker_ValidateResults) (err error) {
+	defer runtime.Recover("Checker.Validate", &err)
+
+	r0, r1 := s.impl.Validate(params.P0)
+
+	results.R0 = r0
+	results.R1 = r1
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "f7d741fa76a4e031acd57ba60a9a714e",
+	ProtocolVersion:  1,
+}
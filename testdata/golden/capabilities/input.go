@@ -0,0 +1,16 @@
+package capabilities
+
+import "io"
+
+// Service is implemented by every plugin.
+//
+//plugingen:optional=Reloader,io.Closer
+type Service interface {
+	Name() string
+}
+
+// Reloader is implemented by plugins which can reload their configuration.
+type Reloader interface {
+	io.Closer
+	Reload(paths ...string) error
+}
@@ -0,0 +1,385 @@
+// Code generated by "plugingen -type=Service"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	capabilities "github.com/jakebailey/plugingen/testdata/golden/capabilities"
+	"io"
+	"net/rpc"
+)
+
+// ReloaderPlugin implements the Plugin interface for Reloader.
+type ReloaderPlugin struct {
+	impl capabilities.Reloader
+}
+
+func NewReloaderPlugin(impl capabilities.Reloader) *ReloaderPlugin {
+	return &ReloaderPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*ReloaderPlugin)(nil) // Compile-time check that ReloaderPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *ReloaderPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewReloaderRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *ReloaderPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewReloaderRPCClient(b, c), nil
+}
+
+// ReloaderRPCClient implements Reloader via net/rpc.
+type ReloaderRPCClient struct {
+	client *runtime.Client
+}
+
+func NewReloaderRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *ReloaderRPCClient {
+	return &ReloaderRPCClient{client: runtime.NewClient("Reloader", b, c, runtime.LogError)}
+}
+
+var _ capabilities.Reloader = (*ReloaderRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *ReloaderRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *ReloaderRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// ReloaderRPCServer implements the net/rpc server for Reloader.
+type ReloaderRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   capabilities.Reloader
+}
+
+func NewReloaderRPCServer(b *goplugin.MuxBroker, impl capabilities.Reloader) *ReloaderRPCServer {
+	return &ReloaderRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Reloader_CloseResults contains results for the Close function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Reloader_CloseResults struct {
+	R0 error
+}
+
+// Close implements Close for the Reloader interface.
+func (c *ReloaderRPCClient) Close() error {
+	results := &Z_Reloader_CloseResults{}
+
+	c.client.Call("Close", nil, results)
+
+	return results.R0
+}
+
+// Close implements the server side of net/rpc calls to Close.
+func (s *ReloaderRPCServer) Close(_ interface{}, results *Z_Reloader_CloseResults) (err error) {
+	defer runtime.Recover("Reloader.Close", &err)
+
+	r0 := s.impl.Close()
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+
+// Z_Reloader_ReloadParams contains parameters for the Reload function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Reloader_ReloadParams struct {
+	P0 []string
+}
+
+// Z_Reloader_ReloadResults contains results for the Reload function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Reloader_ReloadResults struct {
+	R0 error
+}
+
+// Reload implements Reload for the Reloader interface.
+func (c *ReloaderRPCClient) Reload(paths ...string) error {
+	params := &Z_Reloader_ReloadParams{P0: paths}
+	results := &Z_Reloader_ReloadResults{}
+
+	c.client.Call("Reload", params, results)
+
+	return results.R0
+}
+
+// Reload implements the server side of net/rpc calls to Reload.
+func (s *ReloaderRPCServer) Reload(params *Z_Reloader_ReloadParams, results *Z_Reloader_ReloadResults) (err error) {
+	defer runtime.Recover("Reloader.Reload", &err)
+
+	r0 := s.impl.Reload(params.P0...)
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+
+// ServicePlugin implements the Plugin interface for Service.
+type ServicePlugin struct {
+	impl capabilities.Service
+}
+
+func NewServicePlugin(impl capabilities.Service) *ServicePlugin {
+	return &ServicePlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*ServicePlugin)(nil) // Compile-time check that ServicePlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *ServicePlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewServiceRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *ServicePlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewServiceRPCClient(b, c), nil
+}
+
+// ServiceRPCClient implements Service via net/rpc.
+type ServiceRPCClient struct {
+	client *runtime.Client
+}
+
+func NewServiceRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *ServiceRPCClient {
+	return &ServiceRPCClient{client: runtime.NewClient("Service", b, c, runtime.LogError)}
+}
+
+var _ capabilities.Service = (*ServiceRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *ServiceRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *ServiceRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// ServiceRPCServer implements the net/rpc server for Service.
+type ServiceRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   capabilities.Service
+}
+
+func NewServiceRPCServer(b *goplugin.MuxBroker, impl capabilities.Service) *ServiceRPCServer {
+	return &ServiceRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Service_NameResults contains results for the Name function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Service_NameResults struct {
+	R0 string
+}
+
+// Name implements Name for the Service interface.
+func (c *ServiceRPCClient) Name() string {
+	results := &Z_Service_NameResults{}
+
+	c.client.Call("Name", nil, results)
+
+	return results.R0
+}
+
+// Name implements the server side of net/rpc calls to Name.
+func (s *ServiceRPCServer) Name(_ interface{}, results *Z_Service_NameResults) (err error) {
+	defer runtime.Recover("Service.Name", &err)
+
+	r0 := s.impl.Name()
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_Capabilities serves each optional interface implemented by s.impl on a
+// new connection, storing the connection IDs by interface name in caps.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *ServiceRPCServer) Z_Capabilities(_ interface{}, caps *map[string]uint32) error {
+	*caps = map[string]uint32{}
+	if impl, ok := s.impl.(capabilities.Reloader); ok {
+		(*caps)["Reloader"] = runtime.Serve(s.broker, NewReloaderRPCServer(s.broker, impl), "")
+	}
+	if impl, ok := s.impl.(io.Closer); ok {
+		(*caps)["Closer"] = runtime.Serve(s.broker, NewCloserRPCServer(s.broker, impl), "")
+	}
+	return nil
+}
+
+// WithCapabilities asks the plugin which optional interfaces its Service
+// implements, returning a client which implements exactly those as well.
+// If it implements none of them, c is returned. The optional interfaces'
+// connections are closed when c's is replaced, then dialed again.
+func (c *ServiceRPCClient) WithCapabilities() (capabilities.Service, error) {
+	var caps map[string]uint32
+	if err := c.client.Call("Z_Capabilities", nil, &caps); err != nil {
+		return nil, err
+	}
+
+	var mask int
+	var opt0 *ReloaderRPCClient
+	if id, ok := caps["Reloader"]; ok {
+		conn, err := c.client.Dial(id)
+		if err != nil {
+			return nil, err
+		}
+		opt0 = NewReloaderRPCClient(c.client.Broker(), conn)
+		c.client.Attach("Reloader", opt0.client)
+		mask |= 1
+	}
+	var opt1 *CloserRPCClient
+	if id, ok := caps["Closer"]; ok {
+		conn, err := c.client.Dial(id)
+		if err != nil {
+			return nil, err
+		}
+		opt1 = NewCloserRPCClient(c.client.Broker(), conn)
+		c.client.Attach("Closer", opt1.client)
+		mask |= 2
+	}
+
+	switch mask {
+	case 1:
+		return &serviceWithReloader{c, opt0}, nil
+	case 2:
+		return &serviceWithCloser{c, opt1}, nil
+	case 3:
+		return &serviceWithReloaderCloser{c, opt0, opt1}, nil
+	}
+	return c, nil
+}
+
+// serviceWithReloader implements Service, Reloader.
+type serviceWithReloader struct {
+	*ServiceRPCClient
+	*ReloaderRPCClient
+}
+
+var _ capabilities.Reloader = (*serviceWithReloader)(nil)
+
+// serviceWithCloser implements Service, Closer.
+type serviceWithCloser struct {
+	*ServiceRPCClient
+	*CloserRPCClient
+}
+
+var _ io.Closer = (*serviceWithCloser)(nil)
+
+// serviceWithReloaderCloser implements Service, Reloader, Closer.
+type serviceWithReloaderCloser struct {
+	*ServiceRPCClient
+	*ReloaderRPCClient
+	*CloserRPCClient
+}
+
+var _ capabilities.Reloader = (*serviceWithReloaderCloser)(nil)
+var _ io.Closer = (*serviceWithReloaderCloser)(nil)
+
+// Close forwards to ReloaderRPCClient, as more than one interface has it.
+func (w *serviceWithReloaderCloser) Close() error {
+	return w.ReloaderRPCClient.Close()
+}
+
+// CloserPlugin implements the Plugin interface for Closer.
+type CloserPlugin struct {
+	impl io.Closer
+}
+
+func NewCloserPlugin(impl io.Closer) *CloserPlugin {
+	return &CloserPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*CloserPlugin)(nil) // Compile-time check that CloserPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *CloserPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewCloserRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *CloserPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewCloserRPCClient(b, c), nil
+}
+
+// CloserRPCClient implements Closer via net/rpc.
+type CloserRPCClient struct {
+	client *runtime.Client
+}
+
+func NewCloserRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *CloserRPCClient {
+	return &CloserRPCClient{client: runtime.NewClient("Closer", b, c, runtime.LogError)}
+}
+
+var _ io.Closer = (*CloserRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *CloserRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *CloserRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// CloserRPCServer implements the net/rpc server for Closer.
+type CloserRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   io.Closer
+}
+
+func NewCloserRPCServer(b *goplugin.MuxBroker, impl io.Closer) *CloserRPCServer {
+	return &CloserRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Closer_CloseResults contains results for the Close function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Closer_CloseResults struct {
+	R0 error
+}
+
+// Close implements Close for the Closer interface.
+func (c *CloserRPCClient) Close() error {
+	results := &Z_Closer_CloseResults{}
+
+	c.client.Call("Close", nil, results)
+
+	return results.R0
+}
+
+// Close implements the server side of net/rpc calls to Close.
+func (s *CloserRPCServer) Close(_ interface{}, results *Z_Closer_CloseResults) (err error) {
+	defer runtime.Recover("Closer.Close", &err)
+
+	r0 := s.impl.Close()
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "27493801b9ef34f41b6865b8c45aa4f8",
+	ProtocolVersion:  1,
+}
@@ -0,0 +1,14 @@
+package codec
+
+type Pinger interface {
+	Ping()
+	Echo(msg string) (string, error)
+	Watch(w Watcher) error
+
+	//plugingen:oneway
+	Notify(event string)
+}
+
+type Watcher interface {
+	Changed(key string)
+}
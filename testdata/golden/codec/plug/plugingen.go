@@ -0,0 +1,288 @@
+// Code generated by "plugingen -type=Pinger"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	codec "github.com/jakebailey/plugingen/testdata/golden/codec"
+	"net/rpc"
+)
+
+// pluginCodec is the name of the runtime codec used for RPC connections.
+const pluginCodec = "json"
+
+// PingerPlugin implements the Plugin interface for Pinger.
+type PingerPlugin struct {
+	impl codec.Pinger
+}
+
+func NewPingerPlugin(impl codec.Pinger) *PingerPlugin {
+	return &PingerPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*PingerPlugin)(nil) // Compile-time check that PingerPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *PingerPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewPingerRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *PingerPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	c, err := runtime.Connect(b, c, pluginCodec)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPingerRPCClient(b, c), nil
+}
+
+// PingerRPCClient implements Pinger via net/rpc.
+type PingerRPCClient struct {
+	client *runtime.Client
+}
+
+func NewPingerRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *PingerRPCClient {
+	return &PingerRPCClient{client: runtime.NewClient("Pinger", b, c, runtime.LogError, runtime.WithCodec(pluginCodec))}
+}
+
+var _ codec.Pinger = (*PingerRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *PingerRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *PingerRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// PingerRPCServer implements the net/rpc server for Pinger.
+type PingerRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   codec.Pinger
+}
+
+func NewPingerRPCServer(b *goplugin.MuxBroker, impl codec.Pinger) *PingerRPCServer {
+	return &PingerRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Connect serves s on a new connection using the named codec.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *PingerRPCServer) Z_Connect(codec string, id *uint32) error {
+	return runtime.ServeConnect(s.broker, s, codec, id)
+}
+
+// Z_Pinger_EchoParams contains parameters for the Echo function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Pinger_EchoParams struct {
+	P0 string
+}
+
+// Z_Pinger_EchoResults contains results for the Echo function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Pinger_EchoResults struct {
+	R0 string
+	R1 *goplugin.BasicError
+}
+
+// Echo implements Echo for the Pinger interface.
+func (c *PingerRPCClient) Echo(msg string) (string, error) {
+	params := &Z_Pinger_EchoParams{P0: msg}
+	results := &Z_Pinger_EchoResults{}
+
+	c.client.Call("Echo", params, results)
+
+	return results.R0, runtime.FromBasicError(results.R1)
+}
+
+// Echo implements the server side of net/rpc calls to Echo.
+func (s *PingerRPCServer) Echo(params *Z_Pinger_EchoParams, results *Z_Pinger_EchoResults) (err error) {
+	defer runtime.Recover("Pinger.Echo", &err)
+
+	r0, r1 := s.impl.Echo(params.P0)
+
+	results.R0 = r0
+	results.R1 = runtime.ToBasicError(r1)
+
+	return nil
+}
+
+// Z_Pinger_NotifyParams contains parameters for the Notify function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Pinger_NotifyParams struct {
+	P0 string
+}
+
+// Notify implements Notify for the Pinger interface.
+// It does not wait for the call to complete.
+func (c *PingerRPCClient) Notify(event string) {
+	params := &Z_Pinger_NotifyParams{P0: event}
+
+	c.client.Go("Notify", params)
+}
+
+// Notify implements the server side of net/rpc calls to Notify.
+func (s *PingerRPCServer) Notify(params *Z_Pinger_NotifyParams, _ *struct{}) (err error) {
+	defer runtime.Recover("Pinger.Notify", &err)
+
+	s.impl.Notify(params.P0)
+
+	return nil
+}
+
+// Ping implements Ping for the Pinger interface.
+func (c *PingerRPCClient) Ping() {
+	c.client.Call("Ping", nil, nil)
+}
+
+// Ping implements the server side of net/rpc calls to Ping.
+func (s *PingerRPCServer) Ping(_ interface{}, _ *struct{}) (err error) {
+	defer runtime.Recover("Pinger.Ping", &err)
+
+	s.impl.Ping()
+
+	return nil
+}
+
+// Z_Pinger_WatchParams contains parameters for the Watch function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Pinger_WatchParams struct {
+	P0ID uint32
+}
+
+// Z_Pinger_WatchResults contains results for the Watch function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Pinger_WatchResults struct {
+	R0 *goplugin.BasicError
+}
+
+// Watch implements Watch for the Pinger interface.
+func (c *PingerRPCClient) Watch(p0 codec.Watcher) error {
+	params := &Z_Pinger_WatchParams{P0ID: c.client.Serve(NewWatcherRPCServer(c.client.Broker(), p0))}
+	results := &Z_Pinger_WatchResults{}
+
+	c.client.CallWith("Watch", params, results, runtime.CallOptions{Brokered: true})
+
+	return runtime.FromBasicError(results.R0)
+}
+
+// Watch implements the server side of net/rpc calls to Watch.
+func (s *PingerRPCServer) Watch(params *Z_Pinger_WatchParams, results *Z_Pinger_WatchResults) (err error) {
+	defer runtime.Recover("Pinger.Watch", &err)
+
+	p0rpc, err := runtime.DialCodec(s.broker, params.P0ID, pluginCodec)
+	if err != nil {
+		return err
+	}
+	defer p0rpc.Close()
+	p0client := NewWatcherRPCClient(s.broker, p0rpc)
+
+	r0 := s.impl.Watch(p0client)
+
+	results.R0 = runtime.ToBasicError(r0)
+
+	return nil
+}
+
+// WatcherPlugin implements the Plugin interface for Watcher.
+type WatcherPlugin struct {
+	impl codec.Watcher
+}
+
+func NewWatcherPlugin(impl codec.Watcher) *WatcherPlugin {
+	return &WatcherPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*WatcherPlugin)(nil) // Compile-time check that WatcherPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *WatcherPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewWatcherRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *WatcherPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	c, err := runtime.Connect(b, c, pluginCodec)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWatcherRPCClient(b, c), nil
+}
+
+// WatcherRPCClient implements Watcher via net/rpc.
+type WatcherRPCClient struct {
+	client *runtime.Client
+}
+
+func NewWatcherRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *WatcherRPCClient {
+	return &WatcherRPCClient{client: runtime.NewClient("Watcher", b, c, runtime.LogError, runtime.WithCodec(pluginCodec))}
+}
+
+var _ codec.Watcher = (*WatcherRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *WatcherRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *WatcherRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// WatcherRPCServer implements the net/rpc server for Watcher.
+type WatcherRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   codec.Watcher
+}
+
+func NewWatcherRPCServer(b *goplugin.MuxBroker, impl codec.Watcher) *WatcherRPCServer {
+	return &WatcherRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Connect serves s on a new connection using the named codec.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *WatcherRPCServer) Z_Connect(codec string, id *uint32) error {
+	return runtime.ServeConnect(s.broker, s, codec, id)
+}
+
+// Z_Watcher_ChangedParams contains parameters for the Changed function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Watcher_ChangedParams struct {
+	P0 string
+}
+
+// Changed implements Changed for the Watcher interface.
+func (c *WatcherRPCClient) Changed(key string) {
+	params := &Z_Watcher_ChangedParams{P0: key}
+
+	c.client.Call("Changed", params, nil)
+}
+
+// Changed implements the server side of net/rpc calls to Changed.
+func (s *WatcherRPCServer) Changed(params *Z_Watcher_ChangedParams, _ *struct{}) (err error) {
+	defer runtime.Recover("Watcher.Changed", &err)
+
+	s.impl.Changed(params.P0)
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "518835c8442c8aca4464a78cd7751066",
+	ProtocolVersion:  1,
+}
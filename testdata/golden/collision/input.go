@@ -0,0 +1,8 @@
+package collision
+
+import "io"
+
+// Reader shares its name with io.Reader, which is brokered.
+type Reader interface {
+	ReadAll(r io.Reader) (string, error)
+}
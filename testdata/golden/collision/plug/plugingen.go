@@ -0,0 +1,207 @@
+// Code generated by "plugingen -type=Reader"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	collision "github.com/jakebailey/plugingen/testdata/golden/collision"
+	"io"
+	"net/rpc"
+)
+
+// ReaderPlugin implements the Plugin interface for Reader.
+type ReaderPlugin struct {
+	impl collision.Reader
+}
+
+func NewReaderPlugin(impl collision.Reader) *ReaderPlugin {
+	return &ReaderPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*ReaderPlugin)(nil) // Compile-time check that ReaderPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *ReaderPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewReaderRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *ReaderPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewReaderRPCClient(b, c), nil
+}
+
+// ReaderRPCClient implements Reader via net/rpc.
+type ReaderRPCClient struct {
+	client *runtime.Client
+}
+
+func NewReaderRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *ReaderRPCClient {
+	return &ReaderRPCClient{client: runtime.NewClient("Reader", b, c, runtime.LogError)}
+}
+
+var _ collision.Reader = (*ReaderRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *ReaderRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *ReaderRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// ReaderRPCServer implements the net/rpc server for Reader.
+type ReaderRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   collision.Reader
+}
+
+func NewReaderRPCServer(b *goplugin.MuxBroker, impl collision.Reader) *ReaderRPCServer {
+	return &ReaderRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Reader_ReadAllParams contains parameters for the ReadAll function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Reader_ReadAllParams struct {
+	P0ID uint32
+}
+
+// Z_Reader_ReadAllResults contains results for the ReadAll function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Reader_ReadAllResults struct {
+	R0 string
+	R1 error
+}
+
+// ReadAll implements ReadAll for the Reader interface.
+func (c *ReaderRPCClient) ReadAll(r io.Reader) (string, error) {
+	params := &Z_Reader_ReadAllParams{P0ID: c.client.Serve(NewIoReaderRPCServer(c.client.Broker(), r))}
+	results := &Z_Reader_ReadAllResults{}
+
+	c.client.CallWith("ReadAll", params, results, runtime.CallOptions{Brokered: true})
+
+	return results.R0, results.R1
+}
+
+// ReadAll implements the server side of net/rpc calls to ReadAll.
+func (s *ReaderRPCServer) ReadAll(params *Z_Reader_ReadAllParams, results *Z_Reader_ReadAllResults) (err error) {
+	defer runtime.Recover("Reader.ReadAll", &err)
+
+	rrpc, err := runtime.Dial(s.broker, params.P0ID)
+	if err != nil {
+		return err
+	}
+	defer rrpc.Close()
+	rclient := NewIoReaderRPCClient(s.broker, rrpc)
+
+	r0, r1 := s.impl.ReadAll(rclient)
+
+	results.R0 = r0
+	results.R1 = runtime.WrapError(r1)
+
+	return nil
+}
+
+// IoReaderPlugin implements the Plugin interface for IoReader.
+type IoReaderPlugin struct {
+	impl io.Reader
+}
+
+func NewIoReaderPlugin(impl io.Reader) *IoReaderPlugin {
+	return &IoReaderPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*IoReaderPlugin)(nil) // Compile-time check that IoReaderPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *IoReaderPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewIoReaderRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *IoReaderPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewIoReaderRPCClient(b, c), nil
+}
+
+// IoReaderRPCClient implements IoReader via net/rpc.
+type IoReaderRPCClient struct {
+	client *runtime.Client
+}
+
+func NewIoReaderRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *IoReaderRPCClient {
+	return &IoReaderRPCClient{client: runtime.NewClient("IoReader", b, c, runtime.LogError)}
+}
+
+var _ io.Reader = (*IoReaderRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *IoReaderRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *IoReaderRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// IoReaderRPCServer implements the net/rpc server for IoReader.
+type IoReaderRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   io.Reader
+}
+
+func NewIoReaderRPCServer(b *goplugin.MuxBroker, impl io.Reader) *IoReaderRPCServer {
+	return &IoReaderRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_IoReader_ReadParams contains parameters for the Read function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_IoReader_ReadParams struct {
+	P0 []byte
+}
+
+// Z_IoReader_ReadResults contains results for the Read function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_IoReader_ReadResults struct {
+	R0 int
+	R1 error
+}
+
+// Read implements Read for the IoReader interface.
+func (c *IoReaderRPCClient) Read(p []byte) (int, error) {
+	params := &Z_IoReader_ReadParams{P0: p}
+	results := &Z_IoReader_ReadResults{}
+
+	c.client.Call("Read", params, results)
+
+	return results.R0, results.R1
+}
+
+// Read implements the server side of net/rpc calls to Read.
+func (s *IoReaderRPCServer) Read(params *Z_IoReader_ReadParams, results *Z_IoReader_ReadResults) (err error) {
+	defer runtime.Recover("IoReader.Read", &err)
+
+	r0, r1 := s.impl.Read(params.P0)
+
+	results.R0 = r0
+	results.R1 = runtime.WrapError(r1)
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "0b525aaede8afbb954c7a60322fe8cfa",
+	ProtocolVersion:  1,
+}
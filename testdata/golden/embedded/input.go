@@ -0,0 +1,13 @@
+package embedded
+
+import "io"
+
+type Base interface {
+	ID() string
+}
+
+type Store interface {
+	Base
+	io.Closer
+	Get(key string) ([]byte, error)
+}
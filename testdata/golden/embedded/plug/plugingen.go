@@ -0,0 +1,160 @@
+// Code generated by "plugingen -type=Store"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	embedded "github.com/jakebailey/plugingen/testdata/golden/embedded"
+	"net/rpc"
+)
+
+// StorePlugin implements the Plugin interface for Store.
+type StorePlugin struct {
+	impl embedded.Store
+}
+
+func NewStorePlugin(impl embedded.Store) *StorePlugin {
+	return &StorePlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*StorePlugin)(nil) // Compile-time check that StorePlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *StorePlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewStoreRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *StorePlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewStoreRPCClient(b, c), nil
+}
+
+// StoreRPCClient implements Store via net/rpc.
+type StoreRPCClient struct {
+	client *runtime.Client
+}
+
+func NewStoreRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *StoreRPCClient {
+	return &StoreRPCClient{client: runtime.NewClient("Store", b, c, runtime.LogError)}
+}
+
+var _ embedded.Store = (*StoreRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *StoreRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *StoreRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// StoreRPCServer implements the net/rpc server for Store.
+type StoreRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   embedded.Store
+}
+
+func NewStoreRPCServer(b *goplugin.MuxBroker, impl embedded.Store) *StoreRPCServer {
+	return &StoreRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Store_CloseResults contains results for the Close function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Store_CloseResults struct {
+	R0 error
+}
+
+// Close implements Close for the Store interface.
+func (c *StoreRPCClient) Close() error {
+	results := &Z_Store_CloseResults{}
+
+	c.client.Call("Close", nil, results)
+
+	return results.R0
+}
+
+// Close implements the server side of net/rpc calls to Close.
+func (s *StoreRPCServer) Close(_ interface{}, results *Z_Store_CloseResults) (err error) {
+	defer runtime.Recover("Store.Close", &err)
+
+	r0 := s.impl.Close()
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+
+// Z_Store_GetParams contains parameters for the Get function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Store_GetParams struct {
+	P0 string
+}
+
+// Z_Store_GetResults contains results for the Get function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Store_GetResults struct {
+	R0 []byte
+	R1 error
+}
+
+// Get implements Get for the Store interface.
+func (c *StoreRPCClient) Get(key string) ([]byte, error) {
+	params := &Z_Store_GetParams{P0: key}
+	results := &Z_Store_GetResults{}
+
+	c.client.Call("Get", params, results)
+
+	return results.R0, results.R1
+}
+
+// Get implements the server side of net/rpc calls to Get.
+func (s *StoreRPCServer) Get(params *Z_Store_GetParams, results *Z_Store_GetResults) (err error) {
+	defer runtime.Recover("Store.Get", &err)
+
+	r0, r1 := s.impl.Get(params.P0)
+
+	results.R0 = r0
+	results.R1 = runtime.WrapError(r1)
+
+	return nil
+}
+
+// Z_Store_IDResults contains results for the ID function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Store_IDResults struct {
+	R0 string
+}
+
+// ID implements ID for the Store interface.
+func (c *StoreRPCClient) ID() string {
+	results := &Z_Store_IDResults{}
+
+	c.client.Call("ID", nil, results)
+
+	return results.R0
+}
+
+// ID implements the server side of net/rpc calls to ID.
+func (s *StoreRPCServer) ID(_ interface{}, results *Z_Store_IDResults) (err error) {
+	defer runtime.Recover("Store.ID", &err)
+
+	r0 := s.impl.ID()
+
+	results.R0 = r0
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "c995a8d6e36313213aa8f863ab184db3",
+	ProtocolVersion:  1,
+}
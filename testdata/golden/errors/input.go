@@ -0,0 +1,7 @@
+package errors
+
+type Checker interface {
+	Check(err error) error
+	Validate(s string) (bool, error)
+	Errors() []error
+}
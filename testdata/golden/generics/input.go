@@ -0,0 +1,23 @@
+package generics
+
+type User struct {
+	Name string
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Visitor is called for each entry of a Store.
+type Visitor[K comparable, V any] interface {
+	Visit(key K, value V) bool
+}
+
+// Store is a generic key-value store.
+type Store[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Put(key K, value V)
+	Pairs() []Pair[K, V]
+	Each(v Visitor[K, V])
+}
@@ -0,0 +1,547 @@
+// Code generated by "plugingen -type=Store[string, User],Store[int, []byte]"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	generics "github.com/jakebailey/plugingen/testdata/golden/generics"
+	"net/rpc"
+)
+
+// StoreIntSliceBytePlugin implements the Plugin interface for StoreIntSliceByte.
+type StoreIntSliceBytePlugin struct {
+	impl generics.Store[int, []byte]
+}
+
+func NewStoreIntSliceBytePlugin(impl generics.Store[int, []byte]) *StoreIntSliceBytePlugin {
+	return &StoreIntSliceBytePlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*StoreIntSliceBytePlugin)(nil) // Compile-time check that StoreIntSliceBytePlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *StoreIntSliceBytePlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewStoreIntSliceByteRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *StoreIntSliceBytePlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewStoreIntSliceByteRPCClient(b, c), nil
+}
+
+// StoreIntSliceByteRPCClient implements StoreIntSliceByte via net/rpc.
+type StoreIntSliceByteRPCClient struct {
+	client *runtime.Client
+}
+
+func NewStoreIntSliceByteRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *StoreIntSliceByteRPCClient {
+	return &StoreIntSliceByteRPCClient{client: runtime.NewClient("StoreIntSliceByte", b, c, runtime.LogError)}
+}
+
+var _ generics.Store[int, []byte] = (*StoreIntSliceByteRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *StoreIntSliceByteRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *StoreIntSliceByteRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// StoreIntSliceByteRPCServer implements the net/rpc server for StoreIntSliceByte.
+type StoreIntSliceByteRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   generics.Store[int, []byte]
+}
+
+func NewStoreIntSliceByteRPCServer(b *goplugin.MuxBroker, impl generics.Store[int, []byte]) *StoreIntSliceByteRPCServer {
+	return &StoreIntSliceByteRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_StoreIntSliceByte_EachParams contains parameters for the Each function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_StoreIntSliceByte_EachParams struct {
+	P0ID uint32
+}
+
+// Each implements Each for the StoreIntSliceByte interface.
+func (c *StoreIntSliceByteRPCClient) Each(v generics.Visitor[int, []byte]) {
+	params := &Z_StoreIntSliceByte_EachParams{P0ID: c.client.Serve(NewVisitorIntSliceByteRPCServer(c.client.Broker(), v))}
+
+	c.client.CallWith("Each", params, nil, runtime.CallOptions{Brokered: true})
+}
+
+// Each implements the server side of net/rpc calls to Each.
+func (s *StoreIntSliceByteRPCServer) Each(params *Z_StoreIntSliceByte_EachParams, _ *interface{}) (err error) {
+	defer runtime.Recover("StoreIntSliceByte.Each", &err)
+
+	vrpc, err := runtime.Dial(s.broker, params.P0ID)
+	if err != nil {
+		return err
+	}
+	defer vrpc.Close()
+	vclient := NewVisitorIntSliceByteRPCClient(s.broker, vrpc)
+
+	s.impl.Each(vclient)
+
+	return nil
+}
+
+// Z_StoreIntSliceByte_GetParams contains parameters for the Get function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_StoreIntSliceByte_GetParams struct {
+	P0 int
+}
+
+// Z_StoreIntSliceByte_GetResults contains results for the Get function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_StoreIntSliceByte_GetResults struct {
+	R0 []byte
+	R1 bool
+}
+
+// Get implements Get for the StoreIntSliceByte interface.
+func (c *StoreIntSliceByteRPCClient) Get(key int) ([]byte, bool) {
+	params := &Z_StoreIntSliceByte_GetParams{P0: key}
+	results := &Z_StoreIntSliceByte_GetResults{}
+
+	c.client.Call("Get", params, results)
+
+	return results.R0, results.R1
+}
+
+// Get implements the server side of net/rpc calls to Get.
+func (s *StoreIntSliceByteRPCServer) Get(params *Z_StoreIntSliceByte_GetParams, results *Z_StoreIntSliceByte_GetResults) (err error) {
+	defer runtime.Recover("StoreIntSliceByte.Get", &err)
+
+	r0, r1 := s.impl.Get(params.P0)
+
+	results.R0 = r0
+	results.R1 = r1
+
+	return nil
+}
+
+// Z_StoreIntSliceByte_PairsResults contains results for the Pairs function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_StoreIntSliceByte_PairsResults struct {
+	R0 []generics.Pair[int, []byte]
+}
+
+// Pairs implements Pairs for the StoreIntSliceByte interface.
+func (c *StoreIntSliceByteRPCClient) Pairs() []generics.Pair[int, []byte] {
+	results := &Z_StoreIntSliceByte_PairsResults{}
+
+	c.client.Call("Pairs", nil, results)
+
+	return results.R0
+}
+
+// Pairs implements the server side of net/rpc calls to Pairs.
+func (s *StoreIntSliceByteRPCServer) Pairs(_ interface{}, results *Z_StoreIntSliceByte_PairsResults) (err error) {
+	defer runtime.Recover("StoreIntSliceByte.Pairs", &err)
+
+	r0 := s.impl.Pairs()
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_StoreIntSliceByte_PutParams contains parameters for the Put function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_StoreIntSliceByte_PutParams struct {
+	P0 int
+	P1 []byte
+}
+
+// Put implements Put for the StoreIntSliceByte interface.
+func (c *StoreIntSliceByteRPCClient) Put(key int, value []byte) {
+	params := &Z_StoreIntSliceByte_PutParams{
+		P0: key,
+		P1: value,
+	}
+
+	c.client.Call("Put", params, nil)
+}
+
+// Put implements the server side of net/rpc calls to Put.
+func (s *StoreIntSliceByteRPCServer) Put(params *Z_StoreIntSliceByte_PutParams, _ *interface{}) (err error) {
+	defer runtime.Recover("StoreIntSliceByte.Put", &err)
+
+	s.impl.Put(params.P0, params.P1)
+
+	return nil
+}
+
+// StoreStringUserPlugin implements the Plugin interface for StoreStringUser.
+type StoreStringUserPlugin struct {
+	impl generics.Store[string, generics.User]
+}
+
+func NewStoreStringUserPlugin(impl generics.Store[string, generics.User]) *StoreStringUserPlugin {
+	return &StoreStringUserPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*StoreStringUserPlugin)(nil) // Compile-time check that StoreStringUserPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *StoreStringUserPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewStoreStringUserRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *StoreStringUserPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewStoreStringUserRPCClient(b, c), nil
+}
+
+// StoreStringUserRPCClient implements StoreStringUser via net/rpc.
+type StoreStringUserRPCClient struct {
+	client *runtime.Client
+}
+
+func NewStoreStringUserRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *StoreStringUserRPCClient {
+	return &StoreStringUserRPCClient{client: runtime.NewClient("StoreStringUser", b, c, runtime.LogError)}
+}
+
+var _ generics.Store[string, generics.User] = (*StoreStringUserRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *StoreStringUserRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *StoreStringUserRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// StoreStringUserRPCServer implements the net/rpc server for StoreStringUser.
+type StoreStringUserRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   generics.Store[string, generics.User]
+}
+
+func NewStoreStringUserRPCServer(b *goplugin.MuxBroker, impl generics.Store[string, generics.User]) *StoreStringUserRPCServer {
+	return &StoreStringUserRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_StoreStringUser_EachParams contains parameters for the Each function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_StoreStringUser_EachParams struct {
+	P0ID uint32
+}
+
+// Each implements Each for the StoreStringUser interface.
+func (c *StoreStringUserRPCClient) Each(v generics.Visitor[string, generics.User]) {
+	params := &Z_StoreStringUser_EachParams{P0ID: c.client.Serve(NewVisitorStringUserRPCServer(c.client.Broker(), v))}
+
+	c.client.CallWith("Each", params, nil, runtime.CallOptions{Brokered: true})
+}
+
+// Each implements the server side of net/rpc calls to Each.
+func (s *StoreStringUserRPCServer) Each(params *Z_StoreStringUser_EachParams, _ *interface{}) (err error) {
+	defer runtime.Recover("StoreStringUser.Each", &err)
+
+	vrpc, err := runtime.Dial(s.broker, params.P0ID)
+	if err != nil {
+		return err
+	}
+	defer vrpc.Close()
+	vclient := NewVisitorStringUserRPCClient(s.broker, vrpc)
+
+	s.impl.Each(vclient)
+
+	return nil
+}
+
+// Z_StoreStringUser_GetParams contains parameters for the Get function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_StoreStringUser_GetParams struct {
+	P0 string
+}
+
+// Z_StoreStringUser_GetResults contains results for the Get function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_StoreStringUser_GetResults struct {
+	R0 generics.User
+	R1 bool
+}
+
+// Get implements Get for the StoreStringUser interface.
+func (c *StoreStringUserRPCClient) Get(key string) (generics.User, bool) {
+	params := &Z_StoreStringUser_GetParams{P0: key}
+	results := &Z_StoreStringUser_GetResults{}
+
+	c.client.Call("Get", params, results)
+
+	return results.R0, results.R1
+}
+
+// Get implements the server side of net/rpc calls to Get.
+func (s *StoreStringUserRPCServer) Get(params *Z_StoreStringUser_GetParams, results *Z_StoreStringUser_GetResults) (err error) {
+	defer runtime.Recover("StoreStringUser.Get", &err)
+
+	r0, r1 := s.impl.Get(params.P0)
+
+	results.R0 = r0
+	results.R1 = r1
+
+	return nil
+}
+
+// Z_StoreStringUser_PairsResults contains results for the Pairs function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_StoreStringUser_PairsResults struct {
+	R0 []generics.Pair[string, generics.User]
+}
+
+// Pairs implements Pairs for the StoreStringUser interface.
+func (c *StoreStringUserRPCClient) Pairs() []generics.Pair[string, generics.User] {
+	results := &Z_StoreStringUser_PairsResults{}
+
+	c.client.Call("Pairs", nil, results)
+
+	return results.R0
+}
+
+// Pairs implements the server side of net/rpc calls to Pairs.
+func (s *StoreStringUserRPCServer) Pairs(_ interface{}, results *Z_StoreStringUser_PairsResults) (err error) {
+	defer runtime.Recover("StoreStringUser.Pairs", &err)
+
+	r0 := s.impl.Pairs()
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_StoreStringUser_PutParams contains parameters for the Put function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_StoreStringUser_PutParams struct {
+	P0 string
+	P1 generics.User
+}
+
+// Put implements Put for the StoreStringUser interface.
+func (c *StoreStringUserRPCClient) Put(key string, value generics.User) {
+	params := &Z_StoreStringUser_PutParams{
+		P0: key,
+		P1: value,
+	}
+
+	c.client.Call("Put", params, nil)
+}
+
+// Put implements the server side of net/rpc calls to Put.
+func (s *StoreStringUserRPCServer) Put(params *Z_StoreStringUser_PutParams, _ *interface{}) (err error) {
+	defer runtime.Recover("StoreStringUser.Put", &err)
+
+	s.impl.Put(params.P0, params.P1)
+
+	return nil
+}
+
+// VisitorIntSliceBytePlugin implements the Plugin interface for VisitorIntSliceByte.
+type VisitorIntSliceBytePlugin struct {
+	impl generics.Visitor[int, []byte]
+}
+
+func NewVisitorIntSliceBytePlugin(impl generics.Visitor[int, []byte]) *VisitorIntSliceBytePlugin {
+	return &VisitorIntSliceBytePlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*VisitorIntSliceBytePlugin)(nil) // Compile-time check that VisitorIntSliceBytePlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *VisitorIntSliceBytePlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewVisitorIntSliceByteRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *VisitorIntSliceBytePlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewVisitorIntSliceByteRPCClient(b, c), nil
+}
+
+// VisitorIntSliceByteRPCClient implements VisitorIntSliceByte via net/rpc.
+type VisitorIntSliceByteRPCClient struct {
+	client *runtime.Client
+}
+
+func NewVisitorIntSliceByteRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *VisitorIntSliceByteRPCClient {
+	return &VisitorIntSliceByteRPCClient{client: runtime.NewClient("VisitorIntSliceByte", b, c, runtime.LogError)}
+}
+
+var _ generics.Visitor[int, []byte] = (*VisitorIntSliceByteRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *VisitorIntSliceByteRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *VisitorIntSliceByteRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// VisitorIntSliceByteRPCServer implements the net/rpc server for VisitorIntSliceByte.
+type VisitorIntSliceByteRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   generics.Visitor[int, []byte]
+}
+
+func NewVisitorIntSliceByteRPCServer(b *goplugin.MuxBroker, impl generics.Visitor[int, []byte]) *VisitorIntSliceByteRPCServer {
+	return &VisitorIntSliceByteRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_VisitorIntSliceByte_VisitParams contains parameters for the Visit function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_VisitorIntSliceByte_VisitParams struct {
+	P0 int
+	P1 []byte
+}
+
+// Z_VisitorIntSliceByte_VisitResults contains results for the Visit function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_VisitorIntSliceByte_VisitResults struct {
+	R0 bool
+}
+
+// Visit implements Visit for the VisitorIntSliceByte interface.
+func (c *VisitorIntSliceByteRPCClient) Visit(key int, value []byte) bool {
+	params := &Z_VisitorIntSliceByte_VisitParams{
+		P0: key,
+		P1: value,
+	}
+	results := &Z_VisitorIntSliceByte_VisitResults{}
+
+	c.client.Call("Visit", params, results)
+
+	return results.R0
+}
+
+// Visit implements the server side of net/rpc calls to Visit.
+func (s *VisitorIntSliceByteRPCServer) Visit(params *Z_VisitorIntSliceByte_VisitParams, results *Z_VisitorIntSliceByte_VisitResults) (err error) {
+	defer runtime.Recover("VisitorIntSliceByte.Visit", &err)
+
+	r0 := s.impl.Visit(params.P0, params.P1)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// VisitorStringUserPlugin implements the Plugin interface for VisitorStringUser.
+type VisitorStringUserPlugin struct {
+	impl generics.Visitor[string, generics.User]
+}
+
+func NewVisitorStringUserPlugin(impl generics.Visitor[string, generics.User]) *VisitorStringUserPlugin {
+	return &VisitorStringUserPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*VisitorStringUserPlugin)(nil) // Compile-time check that VisitorStringUserPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *VisitorStringUserPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewVisitorStringUserRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *VisitorStringUserPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewVisitorStringUserRPCClient(b, c), nil
+}
+
+// VisitorStringUserRPCClient implements VisitorStringUser via net/rpc.
+type VisitorStringUserRPCClient struct {
+	client *runtime.Client
+}
+
+func NewVisitorStringUserRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *VisitorStringUserRPCClient {
+	return &VisitorStringUserRPCClient{client: runtime.NewClient("VisitorStringUser", b, c, runtime.LogError)}
+}
+
+var _ generics.Visitor[string, generics.User] = (*VisitorStringUserRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *VisitorStringUserRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *VisitorStringUserRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// VisitorStringUserRPCServer implements the net/rpc server for VisitorStringUser.
+type VisitorStringUserRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   generics.Visitor[string, generics.User]
+}
+
+func NewVisitorStringUserRPCServer(b *goplugin.MuxBroker, impl generics.Visitor[string, generics.User]) *VisitorStringUserRPCServer {
+	return &VisitorStringUserRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_VisitorStringUser_VisitParams contains parameters for the Visit function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_VisitorStringUser_VisitParams struct {
+	P0 string
+	P1 generics.User
+}
+
+// Z_VisitorStringUser_VisitResults contains results for the Visit function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_VisitorStringUser_VisitResults struct {
+	R0 bool
+}
+
+// Visit implements Visit for the VisitorStringUser interface.
+func (c *VisitorStringUserRPCClient) Visit(key string, value generics.User) bool {
+	params := &Z_VisitorStringUser_VisitParams{
+		P0: key,
+		P1: value,
+	}
+	results := &Z_VisitorStringUser_VisitResults{}
+
+	c.client.Call("Visit", params, results)
+
+	return results.R0
+}
+
+// Visit implements the server side of net/rpc calls to Visit.
+func (s *VisitorStringUserRPCServer) Visit(params *Z_VisitorStringUser_VisitParams, results *Z_VisitorStringUser_VisitResults) (err error) {
+	defer runtime.Recover("VisitorStringUser.Visit", &err)
+
+	r0 := s.impl.Visit(params.P0, params.P1)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "c64a6731308ba966c6fab4f0d8d486f6",
+	ProtocolVersion:  1,
+}
@@ -0,0 +1,23 @@
+package jsonrpc
+
+// Store is a key-value store.
+type Store interface {
+	Get(key string) ([]byte, error)
+
+	// Scan counts the keys starting with prefix. If it fails, it returns
+	// the number counted so far with the error.
+	Scan(prefix string) (n int, err error)
+
+	Walk(v Visitor) error
+
+	//plugingen:oneway
+	Touch(key string)
+
+	//plugingen:idempotent
+	//plugingen:timeout=1s
+	Len() int
+}
+
+type Visitor interface {
+	Visit(key string, value []byte) bool
+}
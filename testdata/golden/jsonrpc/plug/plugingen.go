@@ -0,0 +1,180 @@
+// Code generated by "plugingen -type=Store"; DO NOT EDIT.
+
+package plug
+
+import (
+	"encoding/json"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	jsonrpc2 "github.com/jakebailey/plugingen/runtime/jsonrpc2"
+	jsonrpc "github.com/jakebailey/plugingen/testdata/golden/jsonrpc"
+)
+
+// StoreJSONClient implements Store via JSON-RPC 2.0.
+type StoreJSONClient struct {
+	client *jsonrpc2.Client
+}
+
+// NewStoreJSONClient returns a client which calls the object with the given ID.
+// The plugin's root object has ID 0.
+func NewStoreJSONClient(conn *jsonrpc2.Conn, object uint64) *StoreJSONClient {
+	return &StoreJSONClient{client: jsonrpc2.NewClient("Store", conn, object, runtime.LogError)}
+}
+
+var _ jsonrpc.Store = (*StoreJSONClient)(nil)
+
+// Get implements Get for the Store interface.
+func (c *StoreJSONClient) Get(key string) ([]byte, error) {
+	var (
+		r0 []byte
+	)
+
+	err := c.client.CallError("Get", []interface{}{key}, &r0)
+
+	return r0, err
+}
+
+// Len implements Len for the Store interface.
+func (c *StoreJSONClient) Len() int {
+	var (
+		r0 int
+	)
+
+	c.client.Call("Len", []interface{}{}, &r0)
+
+	return r0
+}
+
+// Scan implements Scan for the Store interface.
+func (c *StoreJSONClient) Scan(prefix string) (int, error) {
+	var (
+		r0 int
+	)
+
+	err := c.client.CallError("Scan", []interface{}{prefix}, &r0)
+
+	return r0, err
+}
+
+// Touch implements Touch for the Store interface.
+// It does not wait for the call to complete.
+func (c *StoreJSONClient) Touch(key string) {
+	c.client.Notify("Touch", []interface{}{key})
+}
+
+// Walk implements Walk for the Store interface.
+func (c *StoreJSONClient) Walk(v jsonrpc.Visitor) error {
+	vid := c.client.Conn().Register(NewVisitorJSONHandler(c.client.Conn(), v))
+	defer c.client.Conn().Release(vid)
+
+	err := c.client.CallError("Walk", []interface{}{vid})
+
+	return err
+}
+
+// NewStoreJSONHandler returns a handler which calls impl, to be passed to
+// Serve or Register on conn.
+func NewStoreJSONHandler(conn *jsonrpc2.Conn, impl jsonrpc.Store) jsonrpc2.Handler {
+	return func(method string, params []json.RawMessage) ([]interface{}, error) {
+		switch method {
+		case "Get":
+			var (
+				key string
+			)
+			if err := jsonrpc2.UnmarshalParams(params, &key); err != nil {
+				return nil, err
+			}
+
+			r0, r1 := impl.Get(key)
+
+			return []interface{}{r0}, r1
+		case "Len":
+			if err := jsonrpc2.UnmarshalParams(params); err != nil {
+				return nil, err
+			}
+
+			r0 := impl.Len()
+
+			return []interface{}{r0}, nil
+		case "Scan":
+			var (
+				prefix string
+			)
+			if err := jsonrpc2.UnmarshalParams(params, &prefix); err != nil {
+				return nil, err
+			}
+
+			r0, r1 := impl.Scan(prefix)
+
+			return []interface{}{r0}, r1
+		case "Touch":
+			var (
+				key string
+			)
+			if err := jsonrpc2.UnmarshalParams(params, &key); err != nil {
+				return nil, err
+			}
+
+			impl.Touch(key)
+
+			return []interface{}{}, nil
+		case "Walk":
+			var (
+				v uint64
+			)
+			if err := jsonrpc2.UnmarshalParams(params, &v); err != nil {
+				return nil, err
+			}
+
+			r0 := impl.Walk(NewVisitorJSONClient(conn, v))
+
+			return []interface{}{}, r0
+		}
+		return nil, jsonrpc2.MethodNotFound("Store", method)
+	}
+}
+
+// VisitorJSONClient implements Visitor via JSON-RPC 2.0.
+type VisitorJSONClient struct {
+	client *jsonrpc2.Client
+}
+
+// NewVisitorJSONClient returns a client which calls the object with the given ID.
+// The plugin's root object has ID 0.
+func NewVisitorJSONClient(conn *jsonrpc2.Conn, object uint64) *VisitorJSONClient {
+	return &VisitorJSONClient{client: jsonrpc2.NewClient("Visitor", conn, object, runtime.LogError)}
+}
+
+var _ jsonrpc.Visitor = (*VisitorJSONClient)(nil)
+
+// Visit implements Visit for the Visitor interface.
+func (c *VisitorJSONClient) Visit(key string, value []byte) bool {
+	var (
+		r0 bool
+	)
+
+	c.client.Call("Visit", []interface{}{key, value}, &r0)
+
+	return r0
+}
+
+// NewVisitorJSONHandler returns a handler which calls impl, to be passed to
+// Serve or Register on conn.
+func NewVisitorJSONHandler(conn *jsonrpc2.Conn, impl jsonrpc.Visitor) jsonrpc2.Handler {
+	return func(method string, params []json.RawMessage) ([]interface{}, error) {
+		switch method {
+		case "Visit":
+			var (
+				key   string
+				value []byte
+			)
+			if err := jsonrpc2.UnmarshalParams(params, &key, &value); err != nil {
+				return nil, err
+			}
+
+			r0 := impl.Visit(key, value)
+
+			return []interface{}{r0}, nil
+		}
+		return nil, jsonrpc2.MethodNotFound("Visitor", method)
+	}
+}
@@ -0,0 +1,103 @@
+package plug
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/jakebailey/plugingen/runtime/jsonrpc2"
+	"github.com/jakebailey/plugingen/testdata/golden/jsonrpc"
+)
+
+var errInterrupted = errors.New("interrupted")
+
+type store struct {
+	values  map[string][]byte
+	touched chan string
+}
+
+func (s *store) Get(key string) ([]byte, error) {
+	v, ok := s.values[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return v, nil
+}
+
+func (s *store) Scan(prefix string) (int, error) {
+	n := 0
+	for key := range s.values {
+		if strings.HasPrefix(key, prefix) {
+			n++
+		}
+	}
+	return n, errInterrupted
+}
+
+func (s *store) Walk(v jsonrpc.Visitor) error {
+	for key, value := range s.values {
+		if !v.Visit(key, value) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *store) Touch(key string) { s.touched <- key }
+
+func (s *store) Len() int { return len(s.values) }
+
+type visitor map[string]string
+
+func (v visitor) Visit(key string, value []byte) bool {
+	v[key] = string(value)
+	return true
+}
+
+// TestRoundTrip calls each kind of method through the generated client and
+// handler.
+func TestRoundTrip(t *testing.T) {
+	impl := &store{
+		values:  map[string][]byte{"a": []byte("1"), "ab": []byte("2")},
+		touched: make(chan string, 1),
+	}
+
+	a, b := net.Pipe()
+	host, plugin := jsonrpc2.NewConn(a), jsonrpc2.NewConn(b)
+	defer host.Close()
+	defer plugin.Close()
+
+	plugin.Serve(NewStoreJSONHandler(plugin, impl))
+	s := NewStoreJSONClient(host, 0)
+
+	if v, err := s.Get("a"); err != nil || string(v) != "1" {
+		t.Errorf(`Get("a") = %q, %v; want "1", nil`, v, err)
+	}
+
+	if _, err := s.Get("c"); err == nil || err.Error() != "not found" {
+		t.Errorf(`Get("c") error = %v; want not found`, err)
+	}
+
+	// Results returned with an error are kept.
+	if n, err := s.Scan("a"); n != 2 || err == nil || err.Error() != errInterrupted.Error() {
+		t.Errorf(`Scan("a") = %d, %v; want 2, %v`, n, err, errInterrupted)
+	}
+
+	visited := visitor{}
+	if err := s.Walk(visited); err != nil {
+		t.Fatal(err)
+	}
+	if len(visited) != 2 || visited["a"] != "1" || visited["ab"] != "2" {
+		t.Errorf("Walk visited %v; want map[a:1 ab:2]", visited)
+	}
+
+	s.Touch("a")
+	if got := <-impl.touched; got != "a" {
+		t.Errorf("Touch called with %q; want %q", got, "a")
+	}
+
+	if n := s.Len(); n != 2 {
+		t.Errorf("Len() = %d; want 2", n)
+	}
+}
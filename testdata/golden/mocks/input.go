@@ -0,0 +1,7 @@
+package mocks
+
+// Phone has a Calls method, so its mock records calls in Calls2.
+type Phone interface {
+	Dial(number string) error
+	Calls() []string
+}
@@ -0,0 +1,132 @@
+// Code generated by "plugingen -type=Phone"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	mocks "github.com/jakebailey/plugingen/testdata/golden/mocks"
+	"net/rpc"
+)
+
+// PhonePlugin implements the Plugin interface for Phone.
+type PhonePlugin struct {
+	impl mocks.Phone
+}
+
+func NewPhonePlugin(impl mocks.Phone) *PhonePlugin {
+	return &PhonePlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*PhonePlugin)(nil) // Compile-time check that PhonePlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *PhonePlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewPhoneRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *PhonePlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewPhoneRPCClient(b, c), nil
+}
+
+// PhoneRPCClient implements Phone via net/rpc.
+type PhoneRPCClient struct {
+	client *runtime.Client
+}
+
+func NewPhoneRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *PhoneRPCClient {
+	return &PhoneRPCClient{client: runtime.NewClient("Phone", b, c, runtime.LogError)}
+}
+
+var _ mocks.Phone = (*PhoneRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *PhoneRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *PhoneRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// PhoneRPCServer implements the net/rpc server for Phone.
+type PhoneRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   mocks.Phone
+}
+
+func NewPhoneRPCServer(b *goplugin.MuxBroker, impl mocks.Phone) *PhoneRPCServer {
+	return &PhoneRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Phone_CallsResults contains results for the Calls function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Phone_CallsResults struct {
+	R0 []string
+}
+
+// Calls implements Calls for the Phone interface.
+func (c *PhoneRPCClient) Calls() []string {
+	results := &Z_Phone_CallsResults{}
+
+	c.client.Call("Calls", nil, results)
+
+	return results.R0
+}
+
+// Calls implements the server side of net/rpc calls to Calls.
+func (s *PhoneRPCServer) Calls(_ interface{}, results *Z_Phone_CallsResults) (err error) {
+	defer runtime.Recover("Phone.Calls", &err)
+
+	r0 := s.impl.Calls()
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_Phone_DialParams contains parameters for the Dial function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Phone_DialParams struct {
+	P0 string
+}
+
+// Z_Phone_DialResults contains results for the Dial function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Phone_DialResults struct {
+	R0 error
+}
+
+// Dial implements Dial for the Phone interface.
+func (c *PhoneRPCClient) Dial(number string) error {
+	params := &Z_Phone_DialParams{P0: number}
+	results := &Z_Phone_DialResults{}
+
+	c.client.Call("Dial", params, results)
+
+	return results.R0
+}
+
+// Dial implements the server side of net/rpc calls to Dial.
+func (s *PhoneRPCServer) Dial(params *Z_Phone_DialParams, results *Z_Phone_DialResults) (err error) {
+	defer runtime.Recover("Phone.Dial", &err)
+
+	r0 := s.impl.Dial(params.P0)
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "983b6b2d7f44e39b27457eb1178716ad",
+	ProtocolVersion:  1,
+}
@@ -0,0 +1,38 @@
+// Code generated by "plugingen -type=Phone"; DO NOT EDIT.
+
+package plug
+
+import (
+	runtime "github.com/jakebailey/plugingen/runtime"
+	mocks "github.com/jakebailey/plugingen/testdata/golden/mocks"
+)
+
+// PhoneMock is a mock implementation of Phone.
+// Calls are recorded in Calls2, then handled by the matching function
+// field if set, or else return zero values.
+type PhoneMock struct {
+	CallsFunc func() []string
+	DialFunc  func(number string) error
+
+	Calls2 runtime.MockRecorder
+}
+
+var _ mocks.Phone = (*PhoneMock)(nil)
+
+// Calls implements Calls for the Phone interface.
+func (m *PhoneMock) Calls() (r0 []string) {
+	m.Calls2.Record("Calls")
+	if m.CallsFunc != nil {
+		return m.CallsFunc()
+	}
+	return
+}
+
+// Dial implements Dial for the Phone interface.
+func (m *PhoneMock) Dial(number string) (r0 error) {
+	m.Calls2.Record("Dial", number)
+	if m.DialFunc != nil {
+		return m.DialFunc(number)
+	}
+	return
+}
@@ -0,0 +1,9 @@
+package naming
+
+type Finder interface {
+	Find(dir string, patterns ...string) ([]string, error)
+	Each(dir string, fn interface{ Found(path string) bool }) error
+
+	// Resize keeps positional names, as c is the client's receiver.
+	Resize(c int, _ bool)
+}
@@ -0,0 +1,288 @@
+// Code generated by "plugingen -type=Finder"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	naming "github.com/jakebailey/plugingen/testdata/golden/naming"
+	"net/rpc"
+)
+
+// FinderPlugin implements the Plugin interface for Finder.
+type FinderPlugin struct {
+	impl naming.Finder
+}
+
+func NewFinderPlugin(impl naming.Finder) *FinderPlugin {
+	return &FinderPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*FinderPlugin)(nil) // Compile-time check that FinderPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *FinderPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewFinderServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *FinderPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewFinderClient(b, c), nil
+}
+
+// FinderClient implements Finder via net/rpc.
+type FinderClient struct {
+	client *runtime.Client
+}
+
+func NewFinderClient(b *goplugin.MuxBroker, c *rpc.Client) *FinderClient {
+	return &FinderClient{client: runtime.NewClient("Finder", b, c, runtime.LogError)}
+}
+
+var _ naming.Finder = (*FinderClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *FinderClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *FinderClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// FinderServer implements the net/rpc server for Finder.
+type FinderServer struct {
+	broker *goplugin.MuxBroker
+	impl   naming.Finder
+}
+
+func NewFinderServer(b *goplugin.MuxBroker, impl naming.Finder) *FinderServer {
+	return &FinderServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_FinderEachArgs contains parameters for the Each function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_FinderEachArgs struct {
+	Dir  string
+	FnID uint32
+}
+
+// Z_Finder_EachResults contains results for the Each function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Finder_EachResults struct {
+	R0 error
+}
+
+// Each implements Each for the Finder interface.
+func (c *FinderClient) Each(dir string, fn interface {
+	Found(path string) bool
+}) error {
+	params := &Z_FinderEachArgs{
+		Dir:  dir,
+		FnID: c.client.Serve(NewFinderEachFnServer(c.client.Broker(), fn)),
+	}
+	results := &Z_Finder_EachResults{}
+
+	c.client.CallWith("Each", params, results, runtime.CallOptions{Brokered: true})
+
+	return results.R0
+}
+
+// Each implements the server side of net/rpc calls to Each.
+func (s *FinderServer) Each(params *Z_FinderEachArgs, results *Z_Finder_EachResults) (err error) {
+	defer runtime.Recover("Finder.Each", &err)
+
+	fnrpc, err := runtime.Dial(s.broker, params.FnID)
+	if err != nil {
+		return err
+	}
+	defer fnrpc.Close()
+	fnclient := NewFinderEachFnClient(s.broker, fnrpc)
+
+	r0 := s.impl.Each(params.Dir, fnclient)
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+
+// Z_FinderFindArgs contains parameters for the Find function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_FinderFindArgs struct {
+	Dir      string
+	Patterns []string
+}
+
+// Z_Finder_FindResults contains results for the Find function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Finder_FindResults struct {
+	R0 []string
+	R1 error
+}
+
+// Find implements Find for the Finder interface.
+func (c *FinderClient) Find(dir string, patterns ...string) ([]string, error) {
+	params := &Z_FinderFindArgs{
+		Dir:      dir,
+		Patterns: patterns,
+	}
+	results := &Z_Finder_FindResults{}
+
+	c.client.Call("Find", params, results)
+
+	return results.R0, results.R1
+}
+
+// Find implements the server side of net/rpc calls to Find.
+func (s *FinderServer) Find(params *Z_FinderFindArgs, results *Z_Finder_FindResults) (err error) {
+	defer runtime.Recover("Finder.Find", &err)
+
+	r0, r1 := s.impl.Find(params.Dir, params.Patterns...)
+
+	results.R0 = r0
+	results.R1 = runtime.WrapError(r1)
+
+	return nil
+}
+
+// Z_FinderResizeArgs contains parameters for the Resize function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_FinderResizeArgs struct {
+	C    int
+	Arg1 bool
+}
+
+// Resize implements Resize for the Finder interface.
+func (c *FinderClient) Resize(arg0 int, arg1 bool) {
+	params := &Z_FinderResizeArgs{
+		Arg1: arg1,
+		C:    arg0,
+	}
+
+	c.client.Call("Resize", params, nil)
+}
+
+// Resize implements the server side of net/rpc calls to Resize.
+func (s *FinderServer) Resize(params *Z_FinderResizeArgs, _ *interface{}) (err error) {
+	defer runtime.Recover("Finder.Resize", &err)
+
+	s.impl.Resize(params.C, params.Arg1)
+
+	return nil
+}
+
+// FinderEachFn names an untyped interface. It should not be used directly.
+type FinderEachFn interface {
+	Found(path string) bool
+}
+
+// FinderEachFnPlugin implements the Plugin interface for FinderEachFn.
+type FinderEachFnPlugin struct {
+	impl interface {
+		Found(path string) bool
+	}
+}
+
+func NewFinderEachFnPlugin(impl interface {
+	Found(path string) bool
+}) *FinderEachFnPlugin {
+	return &FinderEachFnPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*FinderEachFnPlugin)(nil) // Compile-time check that FinderEachFnPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *FinderEachFnPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewFinderEachFnServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *FinderEachFnPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewFinderEachFnClient(b, c), nil
+}
+
+// FinderEachFnClient implements FinderEachFn via net/rpc.
+type FinderEachFnClient struct {
+	client *runtime.Client
+}
+
+func NewFinderEachFnClient(b *goplugin.MuxBroker, c *rpc.Client) *FinderEachFnClient {
+	return &FinderEachFnClient{client: runtime.NewClient("FinderEachFn", b, c, runtime.LogError)}
+}
+
+var _ interface {
+	Found(path string) bool
+} = (*FinderEachFnClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *FinderEachFnClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *FinderEachFnClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// FinderEachFnServer implements the net/rpc server for FinderEachFn.
+type FinderEachFnServer struct {
+	broker *goplugin.MuxBroker
+	impl   interface {
+		Found(path string) bool
+	}
+}
+
+func NewFinderEachFnServer(b *goplugin.MuxBroker, impl interface {
+	Found(path string) bool
+}) *FinderEachFnServer {
+	return &FinderEachFnServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_FinderEachFnFoundArgs contains parameters for the Found function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_FinderEachFnFoundArgs struct {
+	Path string
+}
+
+// Z_FinderEachFn_FoundResults contains results for the Found function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_FinderEachFn_FoundResults struct {
+	R0 bool
+}
+
+// Found implements Found for the FinderEachFn interface.
+func (c *FinderEachFnClient) Found(path string) bool {
+	params := &Z_FinderEachFnFoundArgs{Path: path}
+	results := &Z_FinderEachFn_FoundResults{}
+
+	c.client.Call("Found", params, results)
+
+	return results.R0
+}
+
+// Found implements the server side of net/rpc calls to Found.
+func (s *FinderEachFnServer) Found(params *Z_FinderEachFnFoundArgs, results *Z_FinderEachFn_FoundResults) (err error) {
+	defer runtime.Recover("FinderEachFn.Found", &err)
+
+	r0 := s.impl.Found(params.Path)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "a2b4b3b7ce77b8496f30fde72722c308",
+	ProtocolVersion:  1,
+}
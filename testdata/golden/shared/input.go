@@ -0,0 +1,20 @@
+package shared
+
+import "io"
+
+// Lifecycle is embedded by each service.
+type Lifecycle interface {
+	io.Closer
+	Start(name string) error
+}
+
+type Cache interface {
+	Lifecycle
+	Get(key string) ([]byte, error)
+}
+
+type Queue interface {
+	Lifecycle
+	Push(item []byte)
+	Close() error
+}
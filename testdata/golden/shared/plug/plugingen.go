@@ -0,0 +1,650 @@
+// Code generated by "plugingen -type=Cache,Queue"; DO NOT EDIT.
+
+package plug
+
+import (
+	"encoding/gob"
+	"fmt"
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	shared "github.com/jakebailey/plugingen/testdata/golden/shared"
+	"io"
+	"net/rpc"
+)
+
+// CachePlugin implements the Plugin interface for Cache.
+type CachePlugin struct {
+	impl shared.Cache
+}
+
+func NewCachePlugin(impl shared.Cache) *CachePlugin {
+	return &CachePlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*CachePlugin)(nil) // Compile-time check that CachePlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *CachePlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewCacheRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *CachePlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewCacheRPCClient(b, c), nil
+}
+
+// CacheRPCClient implements Cache via net/rpc.
+// Methods of embedded interfaces are implemented by the embedded clients.
+type CacheRPCClient struct {
+	client *runtime.Client
+	*LifecycleRPCClient
+}
+
+func NewCacheRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *CacheRPCClient {
+	client := runtime.NewClient("Cache", b, c, runtime.LogError)
+	return &CacheRPCClient{
+		LifecycleRPCClient: &LifecycleRPCClient{
+			CloserRPCClient: &CloserRPCClient{client: client},
+			client:          client,
+		},
+		client: client,
+	}
+}
+
+var _ shared.Cache = (*CacheRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *CacheRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *CacheRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// CacheRPCServer implements the net/rpc server for Cache.
+// Methods of embedded interfaces are served by the embedded servers.
+type CacheRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   shared.Cache
+	*LifecycleRPCServer
+}
+
+func NewCacheRPCServer(b *goplugin.MuxBroker, impl shared.Cache) *CacheRPCServer {
+	return &CacheRPCServer{
+		LifecycleRPCServer: NewLifecycleRPCServer(b, impl),
+		broker:             b,
+		impl:               impl,
+	}
+}
+
+// Z_Cache_GetParams contains parameters for the Get function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Cache_GetParams struct {
+	P0 string
+}
+
+// Z_Cache_GetResults contains results for the Get function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Cache_GetResults struct {
+	R0 []byte
+	R1 error
+}
+
+// Get implements Get for the Cache interface.
+func (c *CacheRPCClient) Get(key string) ([]byte, error) {
+	params := &Z_Cache_GetParams{P0: key}
+	results := &Z_Cache_GetResults{}
+
+	c.client.Call("Get", params, results)
+
+	return results.R0, results.R1
+}
+
+// Get implements the server side of net/rpc calls to Get.
+func (s *CacheRPCServer) Get(params *Z_Cache_GetParams, results *Z_Cache_GetResults) (err error) {
+	defer runtime.Recover("Cache.Get", &err)
+
+	r0, r1 := s.impl.Get(params.P0)
+
+	results.R0 = r0
+	results.R1 = runtime.WrapError(r1)
+
+	return nil
+}
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Closer_CloseResults", &Z_Closer_CloseResults{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Cache_GetParams", &Z_Cache_GetParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Cache_GetResults", &Z_Cache_GetResults{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Lifecycle_StartParams", &Z_Lifecycle_StartParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Lifecycle_StartResults", &Z_Lifecycle_StartResults{})
+}
+
+// CacheBatch queues calls to Cache, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type CacheBatch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new CacheBatch which sends its calls through c.
+func (c *CacheRPCClient) Batch() *CacheBatch {
+	return &CacheBatch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *CacheBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *CacheBatch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// Close queues a call to Close.
+// The returned results are filled in by Flush.
+func (b *CacheBatch) Close() *Z_Closer_CloseResults {
+	results := &Z_Closer_CloseResults{}
+	b.batch.Add("Close", nil, results)
+	return results
+}
+
+// Get queues a call to Get.
+// The returned results are filled in by Flush.
+func (b *CacheBatch) Get(key string) *Z_Cache_GetResults {
+	params := &Z_Cache_GetParams{P0: key}
+	results := &Z_Cache_GetResults{}
+	b.batch.Add("Get", params, results)
+	return results
+}
+
+// Start queues a call to Start.
+// The returned results are filled in by Flush.
+func (b *CacheBatch) Start(name string) *Z_Lifecycle_StartResults {
+	params := &Z_Lifecycle_StartParams{P0: name}
+	results := &Z_Lifecycle_StartResults{}
+	b.batch.Add("Start", params, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *CacheRPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "Close":
+			results := &Z_Closer_CloseResults{}
+			return results, s.Close(nil, results)
+		case "Get":
+			results := &Z_Cache_GetResults{}
+			return results, s.Get(call.Params.(*Z_Cache_GetParams), results)
+		case "Start":
+			results := &Z_Lifecycle_StartResults{}
+			return results, s.Start(call.Params.(*Z_Lifecycle_StartParams), results)
+		}
+		return nil, fmt.Errorf("Cache.%s cannot be batched", call.Method)
+	})
+}
+
+// LifecyclePlugin implements the Plugin interface for Lifecycle.
+type LifecyclePlugin struct {
+	impl shared.Lifecycle
+}
+
+func NewLifecyclePlugin(impl shared.Lifecycle) *LifecyclePlugin {
+	return &LifecyclePlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*LifecyclePlugin)(nil) // Compile-time check that LifecyclePlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *LifecyclePlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewLifecycleRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *LifecyclePlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewLifecycleRPCClient(b, c), nil
+}
+
+// LifecycleRPCClient implements Lifecycle via net/rpc.
+// Methods of embedded interfaces are implemented by the embedded clients.
+type LifecycleRPCClient struct {
+	client *runtime.Client
+	*CloserRPCClient
+}
+
+func NewLifecycleRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *LifecycleRPCClient {
+	client := runtime.NewClient("Lifecycle", b, c, runtime.LogError)
+	return &LifecycleRPCClient{
+		CloserRPCClient: &CloserRPCClient{client: client},
+		client:          client,
+	}
+}
+
+var _ shared.Lifecycle = (*LifecycleRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *LifecycleRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *LifecycleRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// LifecycleRPCServer implements the net/rpc server for Lifecycle.
+// Methods of embedded interfaces are served by the embedded servers.
+type LifecycleRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   shared.Lifecycle
+	*CloserRPCServer
+}
+
+func NewLifecycleRPCServer(b *goplugin.MuxBroker, impl shared.Lifecycle) *LifecycleRPCServer {
+	return &LifecycleRPCServer{
+		CloserRPCServer: NewCloserRPCServer(b, impl),
+		broker:          b,
+		impl:            impl,
+	}
+}
+
+// Z_Lifecycle_StartParams contains parameters for the Start function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Lifecycle_StartParams struct {
+	P0 string
+}
+
+// Z_Lifecycle_StartResults contains results for the Start function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Lifecycle_StartResults struct {
+	R0 error
+}
+
+// Start implements Start for the Lifecycle interface.
+func (c *LifecycleRPCClient) Start(name string) error {
+	params := &Z_Lifecycle_StartParams{P0: name}
+	results := &Z_Lifecycle_StartResults{}
+
+	c.client.Call("Start", params, results)
+
+	return results.R0
+}
+
+// Start implements the server side of net/rpc calls to Start.
+func (s *LifecycleRPCServer) Start(params *Z_Lifecycle_StartParams, results *Z_Lifecycle_StartResults) (err error) {
+	defer runtime.Recover("Lifecycle.Start", &err)
+
+	r0 := s.impl.Start(params.P0)
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Closer_CloseResults", &Z_Closer_CloseResults{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Lifecycle_StartParams", &Z_Lifecycle_StartParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Lifecycle_StartResults", &Z_Lifecycle_StartResults{})
+}
+
+// LifecycleBatch queues calls to Lifecycle, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type LifecycleBatch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new LifecycleBatch which sends its calls through c.
+func (c *LifecycleRPCClient) Batch() *LifecycleBatch {
+	return &LifecycleBatch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *LifecycleBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *LifecycleBatch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// Close queues a call to Close.
+// The returned results are filled in by Flush.
+func (b *LifecycleBatch) Close() *Z_Closer_CloseResults {
+	results := &Z_Closer_CloseResults{}
+	b.batch.Add("Close", nil, results)
+	return results
+}
+
+// Start queues a call to Start.
+// The returned results are filled in by Flush.
+func (b *LifecycleBatch) Start(name string) *Z_Lifecycle_StartResults {
+	params := &Z_Lifecycle_StartParams{P0: name}
+	results := &Z_Lifecycle_StartResults{}
+	b.batch.Add("Start", params, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *LifecycleRPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "Close":
+			results := &Z_Closer_CloseResults{}
+			return results, s.Close(nil, results)
+		case "Start":
+			results := &Z_Lifecycle_StartResults{}
+			return results, s.Start(call.Params.(*Z_Lifecycle_StartParams), results)
+		}
+		return nil, fmt.Errorf("Lifecycle.%s cannot be batched", call.Method)
+	})
+}
+
+// QueuePlugin implements the Plugin interface for Queue.
+type QueuePlugin struct {
+	impl shared.Queue
+}
+
+func NewQueuePlugin(impl shared.Queue) *QueuePlugin {
+	return &QueuePlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*QueuePlugin)(nil) // Compile-time check that QueuePlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *QueuePlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewQueueRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *QueuePlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewQueueRPCClient(b, c), nil
+}
+
+// QueueRPCClient implements Queue via net/rpc.
+// Methods of embedded interfaces are implemented by the embedded clients.
+type QueueRPCClient struct {
+	client *runtime.Client
+	*LifecycleRPCClient
+}
+
+func NewQueueRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *QueueRPCClient {
+	client := runtime.NewClient("Queue", b, c, runtime.LogError)
+	return &QueueRPCClient{
+		LifecycleRPCClient: &LifecycleRPCClient{
+			CloserRPCClient: &CloserRPCClient{client: client},
+			client:          client,
+		},
+		client: client,
+	}
+}
+
+var _ shared.Queue = (*QueueRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *QueueRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *QueueRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// QueueRPCServer implements the net/rpc server for Queue.
+// Methods of embedded interfaces are served by the embedded servers.
+type QueueRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   shared.Queue
+	*LifecycleRPCServer
+}
+
+func NewQueueRPCServer(b *goplugin.MuxBroker, impl shared.Queue) *QueueRPCServer {
+	return &QueueRPCServer{
+		LifecycleRPCServer: NewLifecycleRPCServer(b, impl),
+		broker:             b,
+		impl:               impl,
+	}
+}
+
+// Z_Queue_PushParams contains parameters for the Push function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Queue_PushParams struct {
+	P0 []byte
+}
+
+// Push implements Push for the Queue interface.
+func (c *QueueRPCClient) Push(item []byte) {
+	params := &Z_Queue_PushParams{P0: item}
+
+	c.client.Call("Push", params, nil)
+}
+
+// Push implements the server side of net/rpc calls to Push.
+func (s *QueueRPCServer) Push(params *Z_Queue_PushParams, _ *interface{}) (err error) {
+	defer runtime.Recover("Queue.Push", &err)
+
+	s.impl.Push(params.P0)
+
+	return nil
+}
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Closer_CloseResults", &Z_Closer_CloseResults{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Queue_PushParams", &Z_Queue_PushParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Lifecycle_StartParams", &Z_Lifecycle_StartParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Lifecycle_StartResults", &Z_Lifecycle_StartResults{})
+}
+
+// QueueBatch queues calls to Queue, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type QueueBatch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new QueueBatch which sends its calls through c.
+func (c *QueueRPCClient) Batch() *QueueBatch {
+	return &QueueBatch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *QueueBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *QueueBatch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// Close queues a call to Close.
+// The returned results are filled in by Flush.
+func (b *QueueBatch) Close() *Z_Closer_CloseResults {
+	results := &Z_Closer_CloseResults{}
+	b.batch.Add("Close", nil, results)
+	return results
+}
+
+// Push queues a call to Push.
+func (b *QueueBatch) Push(item []byte) {
+	params := &Z_Queue_PushParams{P0: item}
+	b.batch.Add("Push", params, nil)
+}
+
+// Start queues a call to Start.
+// The returned results are filled in by Flush.
+func (b *QueueBatch) Start(name string) *Z_Lifecycle_StartResults {
+	params := &Z_Lifecycle_StartParams{P0: name}
+	results := &Z_Lifecycle_StartResults{}
+	b.batch.Add("Start", params, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *QueueRPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "Close":
+			results := &Z_Closer_CloseResults{}
+			return results, s.Close(nil, results)
+		case "Push":
+			return nil, s.Push(call.Params.(*Z_Queue_PushParams), nil)
+		case "Start":
+			results := &Z_Lifecycle_StartResults{}
+			return results, s.Start(call.Params.(*Z_Lifecycle_StartParams), results)
+		}
+		return nil, fmt.Errorf("Queue.%s cannot be batched", call.Method)
+	})
+}
+
+// CloserPlugin implements the Plugin interface for Closer.
+type CloserPlugin struct {
+	impl io.Closer
+}
+
+func NewCloserPlugin(impl io.Closer) *CloserPlugin {
+	return &CloserPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*CloserPlugin)(nil) // Compile-time check that CloserPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *CloserPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewCloserRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *CloserPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewCloserRPCClient(b, c), nil
+}
+
+// CloserRPCClient implements Closer via net/rpc.
+type CloserRPCClient struct {
+	client *runtime.Client
+}
+
+func NewCloserRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *CloserRPCClient {
+	return &CloserRPCClient{client: runtime.NewClient("Closer", b, c, runtime.LogError)}
+}
+
+var _ io.Closer = (*CloserRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *CloserRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *CloserRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// CloserRPCServer implements the net/rpc server for Closer.
+type CloserRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   io.Closer
+}
+
+func NewCloserRPCServer(b *goplugin.MuxBroker, impl io.Closer) *CloserRPCServer {
+	return &CloserRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Closer_CloseResults contains results for the Close function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Closer_CloseResults struct {
+	R0 error
+}
+
+// Close implements Close for the Closer interface.
+func (c *CloserRPCClient) Close() error {
+	results := &Z_Closer_CloseResults{}
+
+	c.client.Call("Close", nil, results)
+
+	return results.R0
+}
+
+// Close implements the server side of net/rpc calls to Close.
+func (s *CloserRPCServer) Close(_ interface{}, results *Z_Closer_CloseResults) (err error) {
+	defer runtime.Recover("Closer.Close", &err)
+
+	r0 := s.impl.Close()
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/shared/plug.Z_Closer_CloseResults", &Z_Closer_CloseResults{})
+}
+
+// CloserBatch queues calls to Closer, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type CloserBatch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new CloserBatch which sends its calls through c.
+func (c *CloserRPCClient) Batch() *CloserBatch {
+	return &CloserBatch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *CloserBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *CloserBatch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// Close queues a call to Close.
+// The returned results are filled in by Flush.
+func (b *CloserBatch) Close() *Z_Closer_CloseResults {
+	results := &Z_Closer_CloseResults{}
+	b.batch.Add("Close", nil, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *CloserRPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "Close":
+			results := &Z_Closer_CloseResults{}
+			return results, s.Close(nil, results)
+		}
+		return nil, fmt.Errorf("Closer.%s cannot be batched", call.Method)
+	})
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "ae0ce6582f47b4dae88bc130bb3f0e4d",
+	ProtocolVersion:  1,
+}
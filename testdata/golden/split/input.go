@@ -0,0 +1,12 @@
+package split
+
+// Thinger is split into thinger_plugingen.go.
+type Thinger interface {
+	Thing(name string) (int, error)
+	Visit(v interface{ Enter(name string) bool })
+}
+
+// Writer is split into writer_plugingen.go.
+type Writer interface {
+	Write(p []byte) (int, error)
+}
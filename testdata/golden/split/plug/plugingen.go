@@ -0,0 +1,12 @@
+// Code generated by "plugingen -type=Thinger,Writer"; DO NOT EDIT.
+
+package plug
+
+import goplugin "github.com/hashicorp/go-plugin"
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "c2e1909cc419c312559b848e016b47e5",
+	ProtocolVersion:  1,
+}
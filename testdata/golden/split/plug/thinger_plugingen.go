@@ -0,0 +1,184 @@
+// Code generated by "plugingen -type=Thinger,Writer"; DO NOT EDIT.
+
+package plug
+
+import (
+	"encoding/gob"
+	"fmt"
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	split "github.com/jakebailey/plugingen/testdata/golden/split"
+	"net/rpc"
+)
+
+// ThingerPlugin implements the Plugin interface for Thinger.
+type ThingerPlugin struct {
+	impl split.Thinger
+}
+
+func NewThingerPlugin(impl split.Thinger) *ThingerPlugin {
+	return &ThingerPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*ThingerPlugin)(nil) // Compile-time check that ThingerPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *ThingerPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewThingerRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *ThingerPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewThingerRPCClient(b, c), nil
+}
+
+// ThingerRPCClient implements Thinger via net/rpc.
+type ThingerRPCClient struct {
+	client *runtime.Client
+}
+
+func NewThingerRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *ThingerRPCClient {
+	return &ThingerRPCClient{client: runtime.NewClient("Thinger", b, c, runtime.LogError)}
+}
+
+var _ split.Thinger = (*ThingerRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *ThingerRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *ThingerRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// ThingerRPCServer implements the net/rpc server for Thinger.
+type ThingerRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   split.Thinger
+}
+
+func NewThingerRPCServer(b *goplugin.MuxBroker, impl split.Thinger) *ThingerRPCServer {
+	return &ThingerRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Thinger_ThingParams contains parameters for the Thing function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Thinger_ThingParams struct {
+	P0 string
+}
+
+// Z_Thinger_ThingResults contains results for the Thing function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Thinger_ThingResults struct {
+	R0 int
+	R1 error
+}
+
+// Thing implements Thing for the Thinger interface.
+func (c *ThingerRPCClient) Thing(name string) (int, error) {
+	params := &Z_Thinger_ThingParams{P0: name}
+	results := &Z_Thinger_ThingResults{}
+
+	c.client.Call("Thing", params, results)
+
+	return results.R0, results.R1
+}
+
+// Thing implements the server side of net/rpc calls to Thing.
+func (s *ThingerRPCServer) Thing(params *Z_Thinger_ThingParams, results *Z_Thinger_ThingResults) (err error) {
+	defer runtime.Recover("Thinger.Thing", &err)
+
+	r0, r1 := s.impl.Thing(params.P0)
+
+	results.R0 = r0
+	results.R1 = runtime.WrapError(r1)
+
+	return nil
+}
+
+// Z_Thinger_VisitParams contains parameters for the Visit function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Thinger_VisitParams struct {
+	P0ID uint32
+}
+
+// Visit implements Visit for the Thinger interface.
+func (c *ThingerRPCClient) Visit(v interface {
+	Enter(name string) bool
+}) {
+	params := &Z_Thinger_VisitParams{P0ID: c.client.Serve(NewThingerVisitVRPCServer(c.client.Broker(), v))}
+
+	c.client.CallWith("Visit", params, nil, runtime.CallOptions{Brokered: true})
+}
+
+// Visit implements the server side of net/rpc calls to Visit.
+func (s *ThingerRPCServer) Visit(params *Z_Thinger_VisitParams, _ *interface{}) (err error) {
+	defer runtime.Recover("Thinger.Visit", &err)
+
+	vrpc, err := runtime.Dial(s.broker, params.P0ID)
+	if err != nil {
+		return err
+	}
+	defer vrpc.Close()
+	vclient := NewThingerVisitVRPCClient(s.broker, vrpc)
+
+	s.impl.Visit(vclient)
+
+	return nil
+}
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/split/plug.Z_Thinger_ThingParams", &Z_Thinger_ThingParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/split/plug.Z_Thinger_ThingResults", &Z_Thinger_ThingResults{})
+}
+
+// ThingerBatch queues calls to Thinger, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type ThingerBatch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new ThingerBatch which sends its calls through c.
+func (c *ThingerRPCClient) Batch() *ThingerBatch {
+	return &ThingerBatch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *ThingerBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *ThingerBatch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// Thing queues a call to Thing.
+// The returned results are filled in by Flush.
+func (b *ThingerBatch) Thing(name string) *Z_Thinger_ThingResults {
+	params := &Z_Thinger_ThingParams{P0: name}
+	results := &Z_Thinger_ThingResults{}
+	b.batch.Add("Thing", params, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *ThingerRPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "Thing":
+			results := &Z_Thinger_ThingResults{}
+			return results, s.Thing(call.Params.(*Z_Thinger_ThingParams), results)
+		}
+		return nil, fmt.Errorf("Thinger.%s cannot be batched", call.Method)
+	})
+}
@@ -0,0 +1,165 @@
+// Code generated by "plugingen -type=Thinger,Writer"; DO NOT EDIT.
+
+package plug
+
+import (
+	"encoding/gob"
+	"fmt"
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	"net/rpc"
+)
+
+// ThingerVisitV names an untyped interface. It should not be used directly.
+type ThingerVisitV interface {
+	Enter(name string) bool
+}
+
+// ThingerVisitVPlugin implements the Plugin interface for ThingerVisitV.
+type ThingerVisitVPlugin struct {
+	impl interface {
+		Enter(name string) bool
+	}
+}
+
+func NewThingerVisitVPlugin(impl interface {
+	Enter(name string) bool
+}) *ThingerVisitVPlugin {
+	return &ThingerVisitVPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*ThingerVisitVPlugin)(nil) // Compile-time check that ThingerVisitVPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *ThingerVisitVPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewThingerVisitVRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *ThingerVisitVPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewThingerVisitVRPCClient(b, c), nil
+}
+
+// ThingerVisitVRPCClient implements ThingerVisitV via net/rpc.
+type ThingerVisitVRPCClient struct {
+	client *runtime.Client
+}
+
+func NewThingerVisitVRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *ThingerVisitVRPCClient {
+	return &ThingerVisitVRPCClient{client: runtime.NewClient("ThingerVisitV", b, c, runtime.LogError)}
+}
+
+var _ interface {
+	Enter(name string) bool
+} = (*ThingerVisitVRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *ThingerVisitVRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *ThingerVisitVRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// ThingerVisitVRPCServer implements the net/rpc server for ThingerVisitV.
+type ThingerVisitVRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   interface {
+		Enter(name string) bool
+	}
+}
+
+func NewThingerVisitVRPCServer(b *goplugin.MuxBroker, impl interface {
+	Enter(name string) bool
+}) *ThingerVisitVRPCServer {
+	return &ThingerVisitVRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_ThingerVisitV_EnterParams contains parameters for the Enter function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_ThingerVisitV_EnterParams struct {
+	P0 string
+}
+
+// Z_ThingerVisitV_EnterResults contains results for the Enter function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_ThingerVisitV_EnterResults struct {
+	R0 bool
+}
+
+// Enter implements Enter for the ThingerVisitV interface.
+func (c *ThingerVisitVRPCClient) Enter(name string) bool {
+	params := &Z_ThingerVisitV_EnterParams{P0: name}
+	results := &Z_ThingerVisitV_EnterResults{}
+
+	c.client.Call("Enter", params, results)
+
+	return results.R0
+}
+
+// Enter implements the server side of net/rpc calls to Enter.
+func (s *ThingerVisitVRPCServer) Enter(params *Z_ThingerVisitV_EnterParams, results *Z_ThingerVisitV_EnterResults) (err error) {
+	defer runtime.Recover("ThingerVisitV.Enter", &err)
+
+	r0 := s.impl.Enter(params.P0)
+
+	results.R0 = r0
+
+	return nil
+}
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/split/plug.Z_ThingerVisitV_EnterParams", &Z_ThingerVisitV_EnterParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/split/plug.Z_ThingerVisitV_EnterResults", &Z_ThingerVisitV_EnterResults{})
+}
+
+// ThingerVisitVBatch queues calls to ThingerVisitV, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type ThingerVisitVBatch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new ThingerVisitVBatch which sends its calls through c.
+func (c *ThingerVisitVRPCClient) Batch() *ThingerVisitVBatch {
+	return &ThingerVisitVBatch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *ThingerVisitVBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *ThingerVisitVBatch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// Enter queues a call to Enter.
+// The returned results are filled in by Flush.
+func (b *ThingerVisitVBatch) Enter(name string) *Z_ThingerVisitV_EnterResults {
+	params := &Z_ThingerVisitV_EnterParams{P0: name}
+	results := &Z_ThingerVisitV_EnterResults{}
+	b.batch.Add("Enter", params, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *ThingerVisitVRPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "Enter":
+			results := &Z_ThingerVisitV_EnterResults{}
+			return results, s.Enter(call.Params.(*Z_ThingerVisitV_EnterParams), results)
+		}
+		return nil, fmt.Errorf("ThingerVisitV.%s cannot be batched", call.Method)
+	})
+}
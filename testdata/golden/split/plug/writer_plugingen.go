@@ -0,0 +1,153 @@
+// Code generated by "plugingen -type=Thinger,Writer"; DO NOT EDIT.
+
+package plug
+
+import (
+	"encoding/gob"
+	"fmt"
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	split "github.com/jakebailey/plugingen/testdata/golden/split"
+	"net/rpc"
+)
+
+// WriterPlugin implements the Plugin interface for Writer.
+type WriterPlugin struct {
+	impl split.Writer
+}
+
+func NewWriterPlugin(impl split.Writer) *WriterPlugin {
+	return &WriterPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*WriterPlugin)(nil) // Compile-time check that WriterPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *WriterPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewWriterRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *WriterPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewWriterRPCClient(b, c), nil
+}
+
+// WriterRPCClient implements Writer via net/rpc.
+type WriterRPCClient struct {
+	client *runtime.Client
+}
+
+func NewWriterRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *WriterRPCClient {
+	return &WriterRPCClient{client: runtime.NewClient("Writer", b, c, runtime.LogError)}
+}
+
+var _ split.Writer = (*WriterRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *WriterRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *WriterRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// WriterRPCServer implements the net/rpc server for Writer.
+type WriterRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   split.Writer
+}
+
+func NewWriterRPCServer(b *goplugin.MuxBroker, impl split.Writer) *WriterRPCServer {
+	return &WriterRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Writer_WriteParams contains parameters for the Write function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Writer_WriteParams struct {
+	P0 []byte
+}
+
+// Z_Writer_WriteResults contains results for the Write function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Writer_WriteResults struct {
+	R0 int
+	R1 error
+}
+
+// Write implements Write for the Writer interface.
+func (c *WriterRPCClient) Write(p []byte) (int, error) {
+	params := &Z_Writer_WriteParams{P0: p}
+	results := &Z_Writer_WriteResults{}
+
+	c.client.Call("Write", params, results)
+
+	return results.R0, results.R1
+}
+
+// Write implements the server side of net/rpc calls to Write.
+func (s *WriterRPCServer) Write(params *Z_Writer_WriteParams, results *Z_Writer_WriteResults) (err error) {
+	defer runtime.Recover("Writer.Write", &err)
+
+	r0, r1 := s.impl.Write(params.P0)
+
+	results.R0 = r0
+	results.R1 = runtime.WrapError(r1)
+
+	return nil
+}
+func init() {
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/split/plug.Z_Writer_WriteParams", &Z_Writer_WriteParams{})
+	gob.RegisterName("*github.com/jakebailey/plugingen/testdata/golden/split/plug.Z_Writer_WriteResults", &Z_Writer_WriteResults{})
+}
+
+// WriterBatch queues calls to Writer, sending them to the plugin in a single RPC
+// when flushed. Methods with interface parameters cannot be batched.
+type WriterBatch struct {
+	client *runtime.Client
+	batch  runtime.Batch
+}
+
+// Batch returns a new WriterBatch which sends its calls through c.
+func (c *WriterRPCClient) Batch() *WriterBatch {
+	return &WriterBatch{client: c.client}
+}
+
+// Len returns the number of queued calls.
+func (b *WriterBatch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued calls to the plugin, filling in their results.
+// The calls are executed in order; if one fails, its error is returned and
+// the calls after it are not executed.
+func (b *WriterBatch) Flush() error {
+	return b.client.Flush(&b.batch)
+}
+
+// Write queues a call to Write.
+// The returned results are filled in by Flush.
+func (b *WriterBatch) Write(p []byte) *Z_Writer_WriteResults {
+	params := &Z_Writer_WriteParams{P0: p}
+	results := &Z_Writer_WriteResults{}
+	b.batch.Add("Write", params, results)
+	return results
+}
+
+// Z_Batch implements the server side of batched calls.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *WriterRPCServer) Z_Batch(calls []runtime.BatchCall, results *[]runtime.BatchResult) error {
+	return runtime.ServeBatch(calls, results, func(call runtime.BatchCall) (interface{}, error) {
+		switch call.Method {
+		case "Write":
+			results := &Z_Writer_WriteResults{}
+			return results, s.Write(call.Params.(*Z_Writer_WriteParams), results)
+		}
+		return nil, fmt.Errorf("Writer.%s cannot be batched", call.Method)
+	})
+}
@@ -0,0 +1,11 @@
+package timeouts
+
+//plugingen:timeout=5s
+type Fetcher interface {
+	Fetch(url string) ([]byte, error)
+
+	//plugingen:timeout=100ms
+	Ping()
+
+	Count() int
+}
@@ -0,0 +1,154 @@
+// Code generated by "plugingen -type=Fetcher"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	timeouts "github.com/jakebailey/plugingen/testdata/golden/timeouts"
+	"net/rpc"
+	"time"
+)
+
+// FetcherPlugin implements the Plugin interface for Fetcher.
+type FetcherPlugin struct {
+	impl timeouts.Fetcher
+}
+
+func NewFetcherPlugin(impl timeouts.Fetcher) *FetcherPlugin {
+	return &FetcherPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*FetcherPlugin)(nil) // Compile-time check that FetcherPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *FetcherPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewFetcherRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *FetcherPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewFetcherRPCClient(b, c), nil
+}
+
+// FetcherRPCClient implements Fetcher via net/rpc.
+type FetcherRPCClient struct {
+	client *runtime.Client
+}
+
+func NewFetcherRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *FetcherRPCClient {
+	return &FetcherRPCClient{client: runtime.NewClient("Fetcher", b, c, runtime.LogError, runtime.CloseOnTimeout)}
+}
+
+var _ timeouts.Fetcher = (*FetcherRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *FetcherRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *FetcherRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// FetcherRPCServer implements the net/rpc server for Fetcher.
+type FetcherRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   timeouts.Fetcher
+}
+
+func NewFetcherRPCServer(b *goplugin.MuxBroker, impl timeouts.Fetcher) *FetcherRPCServer {
+	return &FetcherRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Fetcher_CountResults contains results for the Count function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Fetcher_CountResults struct {
+	R0 int
+}
+
+// Count implements Count for the Fetcher interface.
+func (c *FetcherRPCClient) Count() int {
+	results := &Z_Fetcher_CountResults{}
+
+	c.client.CallWith("Count", nil, results, runtime.CallOptions{Timeout: 5 * time.Second})
+
+	return results.R0
+}
+
+// Count implements the server side of net/rpc calls to Count.
+func (s *FetcherRPCServer) Count(_ interface{}, results *Z_Fetcher_CountResults) (err error) {
+	defer runtime.Recover("Fetcher.Count", &err)
+
+	r0 := s.impl.Count()
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_Fetcher_FetchParams contains parameters for the Fetch function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Fetcher_FetchParams struct {
+	P0 string
+}
+
+// Z_Fetcher_FetchResults contains results for the Fetch function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Fetcher_FetchResults struct {
+	R0 []byte
+	R1 error
+}
+
+// Fetch implements Fetch for the Fetcher interface.
+func (c *FetcherRPCClient) Fetch(url string) ([]byte, error) {
+	params := &Z_Fetcher_FetchParams{P0: url}
+	results := &Z_Fetcher_FetchResults{}
+
+	if err := c.client.CallWith("Fetch", params, results, runtime.CallOptions{
+		ReturnsError: true,
+		Timeout:      5 * time.Second,
+	}); err != nil {
+		return results.R0, err
+	}
+
+	return results.R0, results.R1
+}
+
+// Fetch implements the server side of net/rpc calls to Fetch.
+func (s *FetcherRPCServer) Fetch(params *Z_Fetcher_FetchParams, results *Z_Fetcher_FetchResults) (err error) {
+	defer runtime.Recover("Fetcher.Fetch", &err)
+
+	r0, r1 := s.impl.Fetch(params.P0)
+
+	results.R0 = r0
+	results.R1 = runtime.WrapError(r1)
+
+	return nil
+}
+
+// Ping implements Ping for the Fetcher interface.
+func (c *FetcherRPCClient) Ping() {
+	c.client.CallWith("Ping", nil, nil, runtime.CallOptions{Timeout: 100 * time.Millisecond})
+}
+
+// Ping implements the server side of net/rpc calls to Ping.
+func (s *FetcherRPCServer) Ping(_ interface{}, _ *interface{}) (err error) {
+	defer runtime.Recover("Fetcher.Ping", &err)
+
+	s.impl.Ping()
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "48deb9b890414c68467b2239f7c7d5b4",
+	ProtocolVersion:  1,
+}
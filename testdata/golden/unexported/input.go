@@ -0,0 +1,25 @@
+package unexported
+
+import "io"
+
+//plugingen:optional=Flusher
+type Store interface {
+	io.Closer
+
+	Get(key string) ([]byte, error)
+	Walk(v Visitor) error
+
+	//plugingen:oneway
+	Touch(key string)
+
+	//plugingen:skip
+	Lock() func()
+}
+
+type Visitor interface {
+	Visit(key string, value []byte) bool
+}
+
+type Flusher interface {
+	Flush() error
+}
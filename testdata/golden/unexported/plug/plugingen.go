@@ -0,0 +1,585 @@
+// Code generated by "plugingen -type=Store"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	unexported "github.com/jakebailey/plugingen/testdata/golden/unexported"
+	"io"
+	"net/rpc"
+)
+
+// FlusherPlugin implements the Plugin interface for Flusher.
+type FlusherPlugin struct {
+	impl unexported.Flusher
+}
+
+func NewFlusherPlugin(impl unexported.Flusher) *FlusherPlugin {
+	return &FlusherPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*FlusherPlugin)(nil) // Compile-time check that FlusherPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *FlusherPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewFlusherRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *FlusherPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewFlusherRPCClient(b, c), nil
+}
+
+// FlusherRPCClient implements Flusher via net/rpc.
+type FlusherRPCClient struct {
+	client *runtime.Client
+}
+
+func NewFlusherRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *FlusherRPCClient {
+	return &FlusherRPCClient{client: runtime.NewClient("Flusher", b, c, runtime.LogError, runtime.WithDispatch)}
+}
+
+var _ unexported.Flusher = (*FlusherRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *FlusherRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *FlusherRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// FlusherRPCServer implements the net/rpc server for Flusher.
+type FlusherRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   unexported.Flusher
+}
+
+func NewFlusherRPCServer(b *goplugin.MuxBroker, impl unexported.Flusher) *FlusherRPCServer {
+	return &FlusherRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// z_Flusher_FlushResults contains results for the Flush function.
+type z_Flusher_FlushResults struct {
+	R0 error
+}
+
+// Flush implements Flush for the Flusher interface.
+func (c *FlusherRPCClient) Flush() error {
+	results := &z_Flusher_FlushResults{}
+
+	c.client.Call("Flush", nil, results)
+
+	return results.R0
+}
+
+// serveFlush implements the server side of net/rpc calls to Flush.
+func (s *FlusherRPCServer) serveFlush(_ interface{}, results *z_Flusher_FlushResults) (err error) {
+	defer runtime.Recover("Flusher.Flush", &err)
+
+	r0 := s.impl.Flush()
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+
+// Call dispatches a call to the named method of Flusher, decoding its parameters
+// from and encoding its results into the body. It is exported for compatibility
+// with net/rpc and should not be used directly.
+func (s *FlusherRPCServer) Call(call runtime.DispatchCall, body *[]byte) (err error) {
+	switch call.Method {
+	case "Flush":
+		results := &z_Flusher_FlushResults{}
+		if err := s.serveFlush(nil, results); err != nil {
+			return err
+		}
+		*body, err = runtime.Encode(results)
+		return err
+	}
+
+	return &runtime.UnknownMethodError{Method: "Flusher." + call.Method}
+}
+
+// StorePlugin implements the Plugin interface for Store.
+type StorePlugin struct {
+	impl unexported.Store
+}
+
+func NewStorePlugin(impl unexported.Store) *StorePlugin {
+	return &StorePlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*StorePlugin)(nil) // Compile-time check that StorePlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *StorePlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewStoreRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *StorePlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewStoreRPCClient(b, c), nil
+}
+
+// StoreRPCClient implements Store via net/rpc.
+// Methods of embedded interfaces are implemented by the embedded clients.
+type StoreRPCClient struct {
+	client *runtime.Client
+	*CloserRPCClient
+}
+
+func NewStoreRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *StoreRPCClient {
+	client := runtime.NewClient("Store", b, c, runtime.LogError, runtime.WithDispatch)
+	return &StoreRPCClient{
+		CloserRPCClient: &CloserRPCClient{client: client},
+		client:          client,
+	}
+}
+
+var _ unexported.Store = (*StoreRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *StoreRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *StoreRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// StoreRPCServer implements the net/rpc server for Store.
+// Methods of embedded interfaces are served by the embedded servers.
+type StoreRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   unexported.Store
+	*CloserRPCServer
+}
+
+func NewStoreRPCServer(b *goplugin.MuxBroker, impl unexported.Store) *StoreRPCServer {
+	return &StoreRPCServer{
+		CloserRPCServer: NewCloserRPCServer(b, impl),
+		broker:          b,
+		impl:            impl,
+	}
+}
+
+// z_Store_GetParams contains parameters for the Get function.
+type z_Store_GetParams struct {
+	P0 string
+}
+
+// z_Store_GetResults contains results for the Get function.
+type z_Store_GetResults struct {
+	R0 []byte
+	R1 error
+}
+
+// Get implements Get for the Store interface.
+func (c *StoreRPCClient) Get(key string) ([]byte, error) {
+	params := &z_Store_GetParams{P0: key}
+	results := &z_Store_GetResults{}
+
+	c.client.Call("Get", params, results)
+
+	return results.R0, results.R1
+}
+
+// serveGet implements the server side of net/rpc calls to Get.
+func (s *StoreRPCServer) serveGet(params *z_Store_GetParams, results *z_Store_GetResults) (err error) {
+	defer runtime.Recover("Store.Get", &err)
+
+	r0, r1 := s.impl.Get(params.P0)
+
+	results.R0 = r0
+	results.R1 = runtime.WrapError(r1)
+
+	return nil
+}
+
+// Lock implements Lock for the Store interface.
+// It is not available over RPC, and always fails.
+func (c *StoreRPCClient) Lock() func() {
+	c.client.Unavailable("Lock")
+
+	return *new(func())
+}
+
+// z_Store_TouchParams contains parameters for the Touch function.
+type z_Store_TouchParams struct {
+	P0 string
+}
+
+// Touch implements Touch for the Store interface.
+// It does not wait for the call to complete.
+func (c *StoreRPCClient) Touch(key string) {
+	params := &z_Store_TouchParams{P0: key}
+
+	c.client.Go("Touch", params)
+}
+
+// serveTouch implements the server side of net/rpc calls to Touch.
+func (s *StoreRPCServer) serveTouch(params *z_Store_TouchParams, _ *interface{}) (err error) {
+	defer runtime.Recover("Store.Touch", &err)
+
+	s.impl.Touch(params.P0)
+
+	return nil
+}
+
+// z_Store_WalkParams contains parameters for the Walk function.
+type z_Store_WalkParams struct {
+	P0ID uint32
+}
+
+// z_Store_WalkResults contains results for the Walk function.
+type z_Store_WalkResults struct {
+	R0 error
+}
+
+// Walk implements Walk for the Store interface.
+func (c *StoreRPCClient) Walk(v unexported.Visitor) error {
+	params := &z_Store_WalkParams{P0ID: c.client.Serve(NewVisitorRPCServer(c.client.Broker(), v))}
+	results := &z_Store_WalkResults{}
+
+	c.client.CallWith("Walk", params, results, runtime.CallOptions{Brokered: true})
+
+	return results.R0
+}
+
+// serveWalk implements the server side of net/rpc calls to Walk.
+func (s *StoreRPCServer) serveWalk(params *z_Store_WalkParams, results *z_Store_WalkResults) (err error) {
+	defer runtime.Recover("Store.Walk", &err)
+
+	vrpc, err := runtime.Dial(s.broker, params.P0ID)
+	if err != nil {
+		return err
+	}
+	defer vrpc.Close()
+	vclient := NewVisitorRPCClient(s.broker, vrpc)
+
+	r0 := s.impl.Walk(vclient)
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+
+// Call dispatches a call to the named method of Store, decoding its parameters
+// from and encoding its results into the body. It is exported for compatibility
+// with net/rpc and should not be used directly.
+func (s *StoreRPCServer) Call(call runtime.DispatchCall, body *[]byte) (err error) {
+	switch call.Method {
+	case "Close":
+		results := &z_Closer_CloseResults{}
+		if err := s.serveClose(nil, results); err != nil {
+			return err
+		}
+		*body, err = runtime.Encode(results)
+		return err
+	case "Get":
+		params := &z_Store_GetParams{}
+		if err := runtime.Decode(call.Body, params); err != nil {
+			return err
+		}
+		results := &z_Store_GetResults{}
+		if err := s.serveGet(params, results); err != nil {
+			return err
+		}
+		*body, err = runtime.Encode(results)
+		return err
+	case "Touch":
+		params := &z_Store_TouchParams{}
+		if err := runtime.Decode(call.Body, params); err != nil {
+			return err
+		}
+		return s.serveTouch(params, nil)
+	case "Walk":
+		params := &z_Store_WalkParams{}
+		if err := runtime.Decode(call.Body, params); err != nil {
+			return err
+		}
+		results := &z_Store_WalkResults{}
+		if err := s.serveWalk(params, results); err != nil {
+			return err
+		}
+		*body, err = runtime.Encode(results)
+		return err
+	}
+
+	return &runtime.UnknownMethodError{Method: "Store." + call.Method}
+}
+
+// Z_Capabilities serves each optional interface implemented by s.impl on a
+// new connection, storing the connection IDs by interface name in caps.
+// It is exported for compatibility with net/rpc and should not be used directly.
+func (s *StoreRPCServer) Z_Capabilities(_ interface{}, caps *map[string]uint32) error {
+	*caps = map[string]uint32{}
+	if impl, ok := s.impl.(unexported.Flusher); ok {
+		(*caps)["Flusher"] = runtime.Serve(s.broker, NewFlusherRPCServer(s.broker, impl), "")
+	}
+	return nil
+}
+
+// WithCapabilities asks the plugin which optional interfaces its Store
+// implements, returning a client which implements exactly those as well.
+// If it implements none of them, c is returned. The optional interfaces'
+// connections are closed when c's is replaced, then dialed again.
+func (c *StoreRPCClient) WithCapabilities() (unexported.Store, error) {
+	var caps map[string]uint32
+	if err := c.client.Call("Z_Capabilities", nil, &caps); err != nil {
+		return nil, err
+	}
+
+	var mask int
+	var opt0 *FlusherRPCClient
+	if id, ok := caps["Flusher"]; ok {
+		conn, err := c.client.Dial(id)
+		if err != nil {
+			return nil, err
+		}
+		opt0 = NewFlusherRPCClient(c.client.Broker(), conn)
+		c.client.Attach("Flusher", opt0.client)
+		mask |= 1
+	}
+
+	switch mask {
+	case 1:
+		return &storeWithFlusher{c, opt0}, nil
+	}
+	return c, nil
+}
+
+// storeWithFlusher implements Store, Flusher.
+type storeWithFlusher struct {
+	*StoreRPCClient
+	*FlusherRPCClient
+}
+
+var _ unexported.Flusher = (*storeWithFlusher)(nil)
+
+// VisitorPlugin implements the Plugin interface for Visitor.
+type VisitorPlugin struct {
+	impl unexported.Visitor
+}
+
+func NewVisitorPlugin(impl unexported.Visitor) *VisitorPlugin {
+	return &VisitorPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*VisitorPlugin)(nil) // Compile-time check that VisitorPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *VisitorPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewVisitorRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *VisitorPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewVisitorRPCClient(b, c), nil
+}
+
+// VisitorRPCClient implements Visitor via net/rpc.
+type VisitorRPCClient struct {
+	client *runtime.Client
+}
+
+func NewVisitorRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *VisitorRPCClient {
+	return &VisitorRPCClient{client: runtime.NewClient("Visitor", b, c, runtime.LogError, runtime.WithDispatch)}
+}
+
+var _ unexported.Visitor = (*VisitorRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *VisitorRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *VisitorRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// VisitorRPCServer implements the net/rpc server for Visitor.
+type VisitorRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   unexported.Visitor
+}
+
+func NewVisitorRPCServer(b *goplugin.MuxBroker, impl unexported.Visitor) *VisitorRPCServer {
+	return &VisitorRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// z_Visitor_VisitParams contains parameters for the Visit function.
+type z_Visitor_VisitParams struct {
+	P0 string
+	P1 []byte
+}
+
+// z_Visitor_VisitResults contains results for the Visit function.
+type z_Visitor_VisitResults struct {
+	R0 bool
+}
+
+// Visit implements Visit for the Visitor interface.
+func (c *VisitorRPCClient) Visit(key string, value []byte) bool {
+	params := &z_Visitor_VisitParams{
+		P0: key,
+		P1: value,
+	}
+	results := &z_Visitor_VisitResults{}
+
+	c.client.Call("Visit", params, results)
+
+	return results.R0
+}
+
+// serveVisit implements the server side of net/rpc calls to Visit.
+func (s *VisitorRPCServer) serveVisit(params *z_Visitor_VisitParams, results *z_Visitor_VisitResults) (err error) {
+	defer runtime.Recover("Visitor.Visit", &err)
+
+	r0 := s.impl.Visit(params.P0, params.P1)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Call dispatches a call to the named method of Visitor, decoding its parameters
+// from and encoding its results into the body. It is exported for compatibility
+// with net/rpc and should not be used directly.
+func (s *VisitorRPCServer) Call(call runtime.DispatchCall, body *[]byte) (err error) {
+	switch call.Method {
+	case "Visit":
+		params := &z_Visitor_VisitParams{}
+		if err := runtime.Decode(call.Body, params); err != nil {
+			return err
+		}
+		results := &z_Visitor_VisitResults{}
+		if err := s.serveVisit(params, results); err != nil {
+			return err
+		}
+		*body, err = runtime.Encode(results)
+		return err
+	}
+
+	return &runtime.UnknownMethodError{Method: "Visitor." + call.Method}
+}
+
+// CloserPlugin implements the Plugin interface for Closer.
+type CloserPlugin struct {
+	impl io.Closer
+}
+
+func NewCloserPlugin(impl io.Closer) *CloserPlugin {
+	return &CloserPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*CloserPlugin)(nil) // Compile-time check that CloserPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *CloserPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewCloserRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *CloserPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewCloserRPCClient(b, c), nil
+}
+
+// CloserRPCClient implements Closer via net/rpc.
+type CloserRPCClient struct {
+	client *runtime.Client
+}
+
+func NewCloserRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *CloserRPCClient {
+	return &CloserRPCClient{client: runtime.NewClient("Closer", b, c, runtime.LogError, runtime.WithDispatch)}
+}
+
+var _ io.Closer = (*CloserRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *CloserRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *CloserRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// CloserRPCServer implements the net/rpc server for Closer.
+type CloserRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   io.Closer
+}
+
+func NewCloserRPCServer(b *goplugin.MuxBroker, impl io.Closer) *CloserRPCServer {
+	return &CloserRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// z_Closer_CloseResults contains results for the Close function.
+type z_Closer_CloseResults struct {
+	R0 error
+}
+
+// Close implements Close for the Closer interface.
+func (c *CloserRPCClient) Close() error {
+	results := &z_Closer_CloseResults{}
+
+	c.client.Call("Close", nil, results)
+
+	return results.R0
+}
+
+// serveClose implements the server side of net/rpc calls to Close.
+func (s *CloserRPCServer) serveClose(_ interface{}, results *z_Closer_CloseResults) (err error) {
+	defer runtime.Recover("Closer.Close", &err)
+
+	r0 := s.impl.Close()
+
+	results.R0 = runtime.WrapError(r0)
+
+	return nil
+}
+
+// Call dispatches a call to the named method of Closer, decoding its parameters
+// from and encoding its results into the body. It is exported for compatibility
+// with net/rpc and should not be used directly.
+func (s *CloserRPCServer) Call(call runtime.DispatchCall, body *[]byte) (err error) {
+	switch call.Method {
+	case "Close":
+		results := &z_Closer_CloseResults{}
+		if err := s.serveClose(nil, results); err != nil {
+			return err
+		}
+		*body, err = runtime.Encode(results)
+		return err
+	}
+
+	return &runtime.UnknownMethodError{Method: "Closer." + call.Method}
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "fa5cf10680c707eed9caf0a316a1d7bf",
+	ProtocolVersion:  1,
+}
@@ -0,0 +1,157 @@
+package plug
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/jakebailey/plugingen/runtime"
+	"github.com/jakebailey/plugingen/testdata/golden/unexported"
+)
+
+var errNotFound = errors.New("not found")
+
+type store struct {
+	values  map[string][]byte
+	touched chan string
+	flushed bool
+}
+
+func (s *store) Close() error { return nil }
+
+func (s *store) Get(key string) ([]byte, error) {
+	v, ok := s.values[key]
+	if !ok {
+		return nil, errNotFound
+	}
+	return v, nil
+}
+
+func (s *store) Walk(v unexported.Visitor) error {
+	for key, value := range s.values {
+		if !v.Visit(key, value) {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *store) Touch(key string) { s.touched <- key }
+
+func (s *store) Lock() func() { return func() {} }
+
+func (s *store) Flush() error {
+	s.flushed = true
+	return nil
+}
+
+type visitor map[string]string
+
+func (v visitor) Visit(key string, value []byte) bool {
+	v[key] = string(value)
+	return true
+}
+
+// TestRoundTrip calls each kind of method through the Call dispatch of the
+// generated server.
+func TestRoundTrip(t *testing.T) {
+	impl := &store{
+		values:  map[string][]byte{"a": []byte("1")},
+		touched: make(chan string, 1),
+	}
+
+	client, _ := goplugin.TestPluginRPCConn(t, map[string]goplugin.Plugin{"store": NewStorePlugin(impl)}, nil)
+	defer client.Close()
+
+	raw, err := client.Dispense("store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := raw.(*StoreRPCClient)
+
+	if v, err := s.Get("a"); err != nil || string(v) != "1" {
+		t.Errorf(`Get("a") = %q, %v; want "1", nil`, v, err)
+	}
+
+	if _, err := s.Get("b"); err == nil || err.Error() != errNotFound.Error() {
+		t.Errorf(`Get("b") error = %v; want %v`, err, errNotFound)
+	}
+
+	visited := visitor{}
+	if err := s.Walk(visited); err != nil {
+		t.Fatal(err)
+	}
+	if visited["a"] != "1" || len(visited) != 1 {
+		t.Errorf("Walk visited %v; want map[a:1]", visited)
+	}
+
+	s.Touch("a")
+	if got := <-impl.touched; got != "a" {
+		t.Errorf("Touch called with %q; want %q", got, "a")
+	}
+
+	// The connection survives the one-way call.
+	if err := s.Close(); err != nil {
+		t.Errorf("Close() after Touch = %v", err)
+	}
+
+	caps, err := s.WithCapabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, ok := caps.(unexported.Flusher)
+	if !ok {
+		t.Fatalf("WithCapabilities() returned %T; want a Flusher", caps)
+	}
+
+	if err := f.Flush(); err != nil || !impl.flushed {
+		t.Errorf("Flush() = %v, flushed = %v; want nil, true", err, impl.flushed)
+	}
+}
+
+// TestCapabilitiesRedial checks that optional interfaces are dialed again
+// once the plugin has been redispensed.
+func TestCapabilitiesRedial(t *testing.T) {
+	impl := &store{values: map[string][]byte{"a": []byte("1")}}
+	plugins := map[string]goplugin.Plugin{"store": NewStorePlugin(impl)}
+
+	client, _ := goplugin.TestPluginRPCConn(t, plugins, nil)
+	raw, err := client.Dispense("store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := raw.(*StoreRPCClient)
+
+	s.SetRetryPolicy(&runtime.RetryPolicy{
+		Attempts: 1,
+		Redispense: func() (interface{}, error) {
+			client, _ := goplugin.TestPluginRPCConn(t, plugins, nil)
+			t.Cleanup(func() { client.Close() })
+			return client.Dispense("store")
+		},
+	})
+
+	caps, err := s.WithCapabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := caps.(unexported.Flusher)
+
+	// Lose the connection. Calls made on it fail until the client notices
+	// it has been shut down, then redispense the plugin.
+	client.Close()
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if v, _ := s.Get("a"); string(v) == "1" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Get() never succeeded after losing the connection")
+		}
+	}
+
+	if err := f.Flush(); err != nil || !impl.flushed {
+		t.Errorf("Flush() after redispensing = %v, flushed = %v; want nil, true", err, impl.flushed)
+	}
+}
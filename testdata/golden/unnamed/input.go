@@ -0,0 +1,9 @@
+package unnamed
+
+type Mapper interface {
+	Map(s string, f interface{ Apply(string) string }) string
+	Visit(v interface {
+		Enter(name string) bool
+		Leave(name string)
+	})
+}
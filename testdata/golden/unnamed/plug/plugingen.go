@@ -0,0 +1,387 @@
+// Code generated by "plugingen -type=Mapper"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	unnamed "github.com/jakebailey/plugingen/testdata/golden/unnamed"
+	"net/rpc"
+)
+
+// MapperPlugin implements the Plugin interface for Mapper.
+type MapperPlugin struct {
+	impl unnamed.Mapper
+}
+
+func NewMapperPlugin(impl unnamed.Mapper) *MapperPlugin {
+	return &MapperPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*MapperPlugin)(nil) // Compile-time check that MapperPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *MapperPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewMapperRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *MapperPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewMapperRPCClient(b, c), nil
+}
+
+// MapperRPCClient implements Mapper via net/rpc.
+type MapperRPCClient struct {
+	client *runtime.Client
+}
+
+func NewMapperRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *MapperRPCClient {
+	return &MapperRPCClient{client: runtime.NewClient("Mapper", b, c, runtime.LogError)}
+}
+
+var _ unnamed.Mapper = (*MapperRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *MapperRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *MapperRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// MapperRPCServer implements the net/rpc server for Mapper.
+type MapperRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   unnamed.Mapper
+}
+
+func NewMapperRPCServer(b *goplugin.MuxBroker, impl unnamed.Mapper) *MapperRPCServer {
+	return &MapperRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Mapper_MapParams contains parameters for the Map function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Mapper_MapParams struct {
+	P0   string
+	P1ID uint32
+}
+
+// Z_Mapper_MapResults contains results for the Map function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Mapper_MapResults struct {
+	R0 string
+}
+
+// Map implements Map for the Mapper interface.
+func (c *MapperRPCClient) Map(p0 string, p1 interface {
+	Apply(string) string
+}) string {
+	params := &Z_Mapper_MapParams{
+		P0:   p0,
+		P1ID: c.client.Serve(NewMapperMapFRPCServer(c.client.Broker(), p1)),
+	}
+	results := &Z_Mapper_MapResults{}
+
+	c.client.CallWith("Map", params, results, runtime.CallOptions{Brokered: true})
+
+	return results.R0
+}
+
+// Map implements the server side of net/rpc calls to Map.
+func (s *MapperRPCServer) Map(params *Z_Mapper_MapParams, results *Z_Mapper_MapResults) (err error) {
+	defer runtime.Recover("Mapper.Map", &err)
+
+	p1rpc, err := runtime.Dial(s.broker, params.P1ID)
+	if err != nil {
+		return err
+	}
+	defer p1rpc.Close()
+	p1client := NewMapperMapFRPCClient(s.broker, p1rpc)
+
+	r0 := s.impl.Map(params.P0, p1client)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_Mapper_VisitParams contains parameters for the Visit function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Mapper_VisitParams struct {
+	P0ID uint32
+}
+
+// Visit implements Visit for the Mapper interface.
+func (c *MapperRPCClient) Visit(v interface {
+	Enter(name string) bool
+	Leave(name string)
+}) {
+	params := &Z_Mapper_VisitParams{P0ID: c.client.Serve(NewMapperVisitVRPCServer(c.client.Broker(), v))}
+
+	c.client.CallWith("Visit", params, nil, runtime.CallOptions{Brokered: true})
+}
+
+// Visit implements the server side of net/rpc calls to Visit.
+func (s *MapperRPCServer) Visit(params *Z_Mapper_VisitParams, _ *interface{}) (err error) {
+	defer runtime.Recover("Mapper.Visit", &err)
+
+	vrpc, err := runtime.Dial(s.broker, params.P0ID)
+	if err != nil {
+		return err
+	}
+	defer vrpc.Close()
+	vclient := NewMapperVisitVRPCClient(s.broker, vrpc)
+
+	s.impl.Visit(vclient)
+
+	return nil
+}
+
+// MapperMapF names an untyped interface. It should not be used directly.
+type MapperMapF interface {
+	Apply(string) string
+}
+
+// MapperMapFPlugin implements the Plugin interface for MapperMapF.
+type MapperMapFPlugin struct {
+	impl interface {
+		Apply(string) string
+	}
+}
+
+func NewMapperMapFPlugin(impl interface {
+	Apply(string) string
+}) *MapperMapFPlugin {
+	return &MapperMapFPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*MapperMapFPlugin)(nil) // Compile-time check that MapperMapFPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *MapperMapFPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewMapperMapFRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *MapperMapFPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewMapperMapFRPCClient(b, c), nil
+}
+
+// MapperMapFRPCClient implements MapperMapF via net/rpc.
+type MapperMapFRPCClient struct {
+	client *runtime.Client
+}
+
+func NewMapperMapFRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *MapperMapFRPCClient {
+	return &MapperMapFRPCClient{client: runtime.NewClient("MapperMapF", b, c, runtime.LogError)}
+}
+
+var _ interface {
+	Apply(string) string
+} = (*MapperMapFRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *MapperMapFRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *MapperMapFRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// MapperMapFRPCServer implements the net/rpc server for MapperMapF.
+type MapperMapFRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   interface {
+		Apply(string) string
+	}
+}
+
+func NewMapperMapFRPCServer(b *goplugin.MuxBroker, impl interface {
+	Apply(string) string
+}) *MapperMapFRPCServer {
+	return &MapperMapFRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_MapperMapF_ApplyParams contains parameters for the Apply function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_MapperMapF_ApplyParams struct {
+	P0 string
+}
+
+// Z_MapperMapF_ApplyResults contains results for the Apply function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_MapperMapF_ApplyResults struct {
+	R0 string
+}
+
+// Apply implements Apply for the MapperMapF interface.
+func (c *MapperMapFRPCClient) Apply(p0 string) string {
+	params := &Z_MapperMapF_ApplyParams{P0: p0}
+	results := &Z_MapperMapF_ApplyResults{}
+
+	c.client.Call("Apply", params, results)
+
+	return results.R0
+}
+
+// Apply implements the server side of net/rpc calls to Apply.
+func (s *MapperMapFRPCServer) Apply(params *Z_MapperMapF_ApplyParams, results *Z_MapperMapF_ApplyResults) (err error) {
+	defer runtime.Recover("MapperMapF.Apply", &err)
+
+	r0 := s.impl.Apply(params.P0)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// MapperVisitV names an untyped interface. It should not be used directly.
+type MapperVisitV interface {
+	Enter(name string) bool
+	Leave(name string)
+}
+
+// MapperVisitVPlugin implements the Plugin interface for MapperVisitV.
+type MapperVisitVPlugin struct {
+	impl interface {
+		Enter(name string) bool
+		Leave(name string)
+	}
+}
+
+func NewMapperVisitVPlugin(impl interface {
+	Enter(name string) bool
+	Leave(name string)
+}) *MapperVisitVPlugin {
+	return &MapperVisitVPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*MapperVisitVPlugin)(nil) // Compile-time check that MapperVisitVPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *MapperVisitVPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewMapperVisitVRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *MapperVisitVPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewMapperVisitVRPCClient(b, c), nil
+}
+
+// MapperVisitVRPCClient implements MapperVisitV via net/rpc.
+type MapperVisitVRPCClient struct {
+	client *runtime.Client
+}
+
+func NewMapperVisitVRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *MapperVisitVRPCClient {
+	return &MapperVisitVRPCClient{client: runtime.NewClient("MapperVisitV", b, c, runtime.LogError)}
+}
+
+var _ interface {
+	Enter(name string) bool
+	Leave(name string)
+} = (*MapperVisitVRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *MapperVisitVRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *MapperVisitVRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// MapperVisitVRPCServer implements the net/rpc server for MapperVisitV.
+type MapperVisitVRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   interface {
+		Enter(name string) bool
+		Leave(name string)
+	}
+}
+
+func NewMapperVisitVRPCServer(b *goplugin.MuxBroker, impl interface {
+	Enter(name string) bool
+	Leave(name string)
+}) *MapperVisitVRPCServer {
+	return &MapperVisitVRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_MapperVisitV_EnterParams contains parameters for the Enter function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_MapperVisitV_EnterParams struct {
+	P0 string
+}
+
+// Z_MapperVisitV_EnterResults contains results for the Enter function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_MapperVisitV_EnterResults struct {
+	R0 bool
+}
+
+// Enter implements Enter for the MapperVisitV interface.
+func (c *MapperVisitVRPCClient) Enter(name string) bool {
+	params := &Z_MapperVisitV_EnterParams{P0: name}
+	results := &Z_MapperVisitV_EnterResults{}
+
+	c.client.Call("Enter", params, results)
+
+	return results.R0
+}
+
+// Enter implements the server side of net/rpc calls to Enter.
+func (s *MapperVisitVRPCServer) Enter(params *Z_MapperVisitV_EnterParams, results *Z_MapperVisitV_EnterResults) (err error) {
+	defer runtime.Recover("MapperVisitV.Enter", &err)
+
+	r0 := s.impl.Enter(params.P0)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_MapperVisitV_LeaveParams contains parameters for the Leave function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_MapperVisitV_LeaveParams struct {
+	P0 string
+}
+
+// Leave implements Leave for the MapperVisitV interface.
+func (c *MapperVisitVRPCClient) Leave(name string) {
+	params := &Z_MapperVisitV_LeaveParams{P0: name}
+
+	c.client.Call("Leave", params, nil)
+}
+
+// Leave implements the server side of net/rpc calls to Leave.
+func (s *MapperVisitVRPCServer) Leave(params *Z_MapperVisitV_LeaveParams, _ *interface{}) (err error) {
+	defer runtime.Recover("MapperVisitV.Leave", &err)
+
+	s.impl.Leave(params.P0)
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "e478887545a98a8afbe446de9f599227",
+	ProtocolVersion:  1,
+}
@@ -0,0 +1,7 @@
+package variadic
+
+type Joiner interface {
+	Join(sep string, parts ...string) string
+	Max(first int, rest ...int) int
+	Sum(...int) int
+}
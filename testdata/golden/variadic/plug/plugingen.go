@@ -0,0 +1,180 @@
+// Code generated by "plugingen -type=Joiner"; DO NOT EDIT.
+
+package plug
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+	runtime "github.com/jakebailey/plugingen/runtime"
+	variadic "github.com/jakebailey/plugingen/testdata/golden/variadic"
+	"net/rpc"
+)
+
+// JoinerPlugin implements the Plugin interface for Joiner.
+type JoinerPlugin struct {
+	impl variadic.Joiner
+}
+
+func NewJoinerPlugin(impl variadic.Joiner) *JoinerPlugin {
+	return &JoinerPlugin{impl: impl}
+}
+
+var _ goplugin.Plugin = (*JoinerPlugin)(nil) // Compile-time check that JoinerPlugin is a Plugin.
+
+// Server implements the Server method for the Plugin interface.
+func (p *JoinerPlugin) Server(b *goplugin.MuxBroker) (interface{}, error) {
+	return NewJoinerRPCServer(b, p.impl), nil
+}
+
+// Client implements the Client method for the Plugin interface.
+func (p *JoinerPlugin) Client(b *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return NewJoinerRPCClient(b, c), nil
+}
+
+// JoinerRPCClient implements Joiner via net/rpc.
+type JoinerRPCClient struct {
+	client *runtime.Client
+}
+
+func NewJoinerRPCClient(b *goplugin.MuxBroker, c *rpc.Client) *JoinerRPCClient {
+	return &JoinerRPCClient{client: runtime.NewClient("Joiner", b, c, runtime.LogError)}
+}
+
+var _ variadic.Joiner = (*JoinerRPCClient)(nil)
+
+// SetRetryPolicy sets the policy used to retry calls to idempotent methods.
+func (c *JoinerRPCClient) SetRetryPolicy(p *runtime.RetryPolicy) {
+	c.client.SetRetryPolicy(p)
+}
+
+// Z_RuntimeClient returns the underlying runtime.Client.
+// It is exported for use by the runtime package and should not be used directly.
+func (c *JoinerRPCClient) Z_RuntimeClient() *runtime.Client {
+	return c.client
+}
+
+// JoinerRPCServer implements the net/rpc server for Joiner.
+type JoinerRPCServer struct {
+	broker *goplugin.MuxBroker
+	impl   variadic.Joiner
+}
+
+func NewJoinerRPCServer(b *goplugin.MuxBroker, impl variadic.Joiner) *JoinerRPCServer {
+	return &JoinerRPCServer{
+		broker: b,
+		impl:   impl,
+	}
+}
+
+// Z_Joiner_JoinParams contains parameters for the Join function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Joiner_JoinParams struct {
+	P0 string
+	P1 []string
+}
+
+// Z_Joiner_JoinResults contains results for the Join function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Joiner_JoinResults struct {
+	R0 string
+}
+
+// Join implements Join for the Joiner interface.
+func (c *JoinerRPCClient) Join(sep string, parts ...string) string {
+	params := &Z_Joiner_JoinParams{
+		P0: sep,
+		P1: parts,
+	}
+	results := &Z_Joiner_JoinResults{}
+
+	c.client.Call("Join", params, results)
+
+	return results.R0
+}
+
+// Join implements the server side of net/rpc calls to Join.
+func (s *JoinerRPCServer) Join(params *Z_Joiner_JoinParams, results *Z_Joiner_JoinResults) (err error) {
+	defer runtime.Recover("Joiner.Join", &err)
+
+	r0 := s.impl.Join(params.P0, params.P1...)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_Joiner_MaxParams contains parameters for the Max function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Joiner_MaxParams struct {
+	P0 int
+	P1 []int
+}
+
+// Z_Joiner_MaxResults contains results for the Max function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Joiner_MaxResults struct {
+	R0 int
+}
+
+// Max implements Max for the Joiner interface.
+func (c *JoinerRPCClient) Max(first int, rest ...int) int {
+	params := &Z_Joiner_MaxParams{
+		P0: first,
+		P1: rest,
+	}
+	results := &Z_Joiner_MaxResults{}
+
+	c.client.Call("Max", params, results)
+
+	return results.R0
+}
+
+// Max implements the server side of net/rpc calls to Max.
+func (s *JoinerRPCServer) Max(params *Z_Joiner_MaxParams, results *Z_Joiner_MaxResults) (err error) {
+	defer runtime.Recover("Joiner.Max", &err)
+
+	r0 := s.impl.Max(params.P0, params.P1...)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// Z_Joiner_SumParams contains parameters for the Sum function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Joiner_SumParams struct {
+	P0 []int
+}
+
+// Z_Joiner_SumResults contains results for the Sum function.
+// It is exported for compatibility with net/rpc and should not be used directly.
+type Z_Joiner_SumResults struct {
+	R0 int
+}
+
+// Sum implements Sum for the Joiner interface.
+func (c *JoinerRPCClient) Sum(p0 ...int) int {
+	params := &Z_Joiner_SumParams{P0: p0}
+	results := &Z_Joiner_SumResults{}
+
+	c.client.Call("Sum", params, results)
+
+	return results.R0
+}
+
+// Sum implements the server side of net/rpc calls to Sum.
+func (s *JoinerRPCServer) Sum(params *Z_Joiner_SumParams, results *Z_Joiner_SumResults) (err error) {
+	defer runtime.Recover("Joiner.Sum", &err)
+
+	r0 := s.impl.Sum(params.P0...)
+
+	results.R0 = r0
+
+	return nil
+}
+
+// PluginHandshake is a plugin handshake generated from the input interfaces.
+var PluginHandshake = goplugin.HandshakeConfig{
+	MagicCookieKey:   "PLUGINGEN_MAGIC_COOKIE_KEY",
+	MagicCookieValue: "9f0506eb7f54afd0a699bdd5f4ead89c",
+	ProtocolVersion:  1,
+}
@@ -1,26 +1,23 @@
 // Package tojen implements conversion from go/types to jen statements.
+//
+// Named types and aliases are rendered by name, never by their underlying
+// type, so that the rendered type is identical to the original, and so that
+// recursive types terminate.
 package tojen
 
 import (
-	"fmt"
 	"go/types"
+	"strconv"
 	"strings"
 
 	"github.com/dave/jennifer/jen"
 )
 
 func Type(t types.Type) *jen.Statement {
-	return typeToJen(t, nil)
+	return typeToJen(t)
 }
 
-func typeToJen(t types.Type, visited []types.Type) *jen.Statement {
-	for _, v := range visited {
-		if v == t {
-			return jen.Id(fmt.Sprintf("○%T", t))
-		}
-	}
-	visited = append(visited, t)
-
+func typeToJen(t types.Type) *jen.Statement {
 	switch t := t.(type) {
 	case nil:
 		return jen.Id("<nil>")
@@ -32,10 +29,10 @@ func typeToJen(t types.Type, visited []types.Type) *jen.Statement {
 		return jen.Id(t.Name())
 
 	case *types.Array:
-		return jen.Index(jen.Lit(t.Len())).Add(typeToJen(t.Elem(), visited))
+		return jen.Index(jen.Lit(t.Len())).Add(typeToJen(t.Elem()))
 
 	case *types.Slice:
-		return jen.Index().Add(typeToJen(t.Elem(), visited))
+		return jen.Index().Add(typeToJen(t.Elem()))
 
 	case *types.Struct:
 		numFields := t.NumFields()
@@ -48,21 +45,22 @@ func typeToJen(t types.Type, visited []types.Type) *jen.Statement {
 					code = code.Id(field.Name())
 				}
 
-				code.Add(typeToJen(field.Type(), visited))
+				code.Add(typeToJen(field.Type()))
 
-				// TODO: tag rendering (unsupported by jen without reparsing)
-				// code.Tag(t.Tag(i))
+				if tag := t.Tag(i); tag != "" {
+					code.Add(tagToJen(tag))
+				}
 			}
 		})
 
 	case *types.Pointer:
-		return jen.Op("*").Add(typeToJen(t.Elem(), visited))
+		return jen.Op("*").Add(typeToJen(t.Elem()))
 
 	case *types.Tuple:
-		return tupleToJen(t, false, visited)
+		return tupleToJen(t, false)
 
 	case *types.Signature:
-		return jen.Func().Add(signatureToJen(t, visited))
+		return jen.Func().Add(signatureToJen(t))
 
 	case *types.Interface:
 		return jen.InterfaceFunc(func(g *jen.Group) {
@@ -70,19 +68,19 @@ func typeToJen(t types.Type, visited []types.Type) *jen.Statement {
 
 			for i := 0; i < numMethods; i++ {
 				m := t.ExplicitMethod(i)
-				g.Id(m.Name()).Add(signatureToJen(m.Type().(*types.Signature), visited))
+				g.Id(m.Name()).Add(signatureToJen(m.Type().(*types.Signature)))
 			}
 
 			numEmbeddeds := t.NumEmbeddeds()
 
 			for i := 0; i < numEmbeddeds; i++ {
 				typ := t.EmbeddedType(i)
-				g.Add(typeToJen(typ, visited))
+				g.Add(typeToJen(typ))
 			}
 		})
 
 	case *types.Map:
-		return jen.Map(typeToJen(t.Key(), visited)).Add(typeToJen(t.Elem(), visited))
+		return jen.Map(typeToJen(t.Key())).Add(typeToJen(t.Elem()))
 
 	case *types.Chan:
 		var code *jen.Statement
@@ -106,7 +104,7 @@ func typeToJen(t types.Type, visited []types.Type) *jen.Statement {
 			panic("unreachable")
 		}
 
-		j := typeToJen(t.Elem(), visited)
+		j := typeToJen(t.Elem())
 
 		if parens {
 			return code.Parens(j)
@@ -115,30 +113,80 @@ func typeToJen(t types.Type, visited []types.Type) *jen.Statement {
 		return code.Add(j)
 
 	case *types.Named:
-		if obj := t.Obj(); obj != nil {
-			if pkg := obj.Pkg(); pkg != nil {
-				path := pkg.Path()
-
-				// TODO: Fix this hack for vendored dependencies.
-				if i := strings.LastIndex(path, "/vendor/"); i != -1 {
-					i += len("/vendor/")
-					path = path[i:]
-				}
+		return typeArgsToJen(objToJen(t.Obj()), t.TypeArgs())
+
+	case *types.Alias:
+		return typeArgsToJen(objToJen(t.Obj()), t.TypeArgs())
+
+	case *types.TypeParam:
+		return jen.Id(t.Obj().Name())
 
-				return jen.Qual(path, obj.Name())
+	case *types.Union:
+		code := jen.Null()
+		for i := 0; i < t.Len(); i++ {
+			if i != 0 {
+				code.Op("|")
 			}
-			return jen.Id(obj.Name())
+			term := t.Term(i)
+			if term.Tilde() {
+				code.Op("~")
+			}
+			code.Add(typeToJen(term.Type()))
 		}
+		return code
 	}
 
 	panic("unreachable")
 }
 
+// objToJen returns a reference to the type name obj, qualified by its
+// package if it has one.
+func objToJen(obj *types.TypeName) *jen.Statement {
+	pkg := obj.Pkg()
+	if pkg == nil {
+		return jen.Id(obj.Name())
+	}
+
+	path := pkg.Path()
+
+	// TODO: Fix this hack for vendored dependencies.
+	if i := strings.LastIndex(path, "/vendor/"); i != -1 {
+		i += len("/vendor/")
+		path = path[i:]
+	}
+
+	return jen.Qual(path, obj.Name())
+}
+
+// typeArgsToJen appends the type arguments of an instantiated type to code.
+// This version of jen cannot render type arguments itself, but the brackets
+// are fixed up when the output is formatted.
+func typeArgsToJen(code *jen.Statement, args *types.TypeList) *jen.Statement {
+	if args.Len() == 0 {
+		return code
+	}
+
+	return code.Op("[").ListFunc(func(g *jen.Group) {
+		for i := 0; i < args.Len(); i++ {
+			g.Add(typeToJen(args.At(i)))
+		}
+	}).Op("]")
+}
+
+// tagToJen renders a struct tag as a string literal. jen's Tag only renders
+// key-value pairs from a map, which would reorder or drop parts of the tag.
+func tagToJen(tag string) *jen.Statement {
+	if strconv.CanBackquote(tag) {
+		return jen.Op("`" + tag + "`")
+	}
+	return jen.Op(strconv.Quote(tag))
+}
+
 func Tuple(t *types.Tuple, variadic bool) *jen.Statement {
-	return tupleToJen(t, variadic, nil)
+	return tupleToJen(t, variadic)
 }
 
-func tupleToJen(t *types.Tuple, variadic bool, visited []types.Type) *jen.Statement {
+func tupleToJen(t *types.Tuple, variadic bool) *jen.Statement {
 	return jen.ParamsFunc(func(g *jen.Group) {
 		tupleLen := t.Len()
 
@@ -148,7 +196,7 @@ func tupleToJen(t *types.Tuple, variadic bool, visited []types.Type) *jen.Statem
 
 		for i := 0; i < tupleLen; i++ {
 			v := t.At(i)
-			j := typeToJen(v.Type(), visited)
+			j := typeToJen(v.Type())
 			name := v.Name()
 
 			if name == "" {
@@ -176,23 +224,23 @@ func tupleToJen(t *types.Tuple, variadic bool, visited []types.Type) *jen.Statem
 					panic("internal error: string type expected")
 				}
 
-				j := typeToJen(typ, visited)
+				j := typeToJen(typ)
 				code.Add(j).Op("...")
 				return
 			}
 
-			j := typeToJen(typ, visited)
+			j := typeToJen(typ)
 			code.Add(j)
 		}
 	})
 }
 
 func Signature(t *types.Signature) *jen.Statement {
-	return signatureToJen(t, nil)
+	return signatureToJen(t)
 }
 
-func signatureToJen(t *types.Signature, visited []types.Type) *jen.Statement {
-	code := tupleToJen(t.Params(), t.Variadic(), visited)
+func signatureToJen(t *types.Signature) *jen.Statement {
+	code := tupleToJen(t.Params(), t.Variadic())
 
 	n := t.Results().Len()
 
@@ -201,8 +249,8 @@ func signatureToJen(t *types.Signature, visited []types.Type) *jen.Statement {
 	}
 
 	if n == 1 && t.Results().At(0).Name() == "" {
-		return code.Add(typeToJen(t.Results().At(0).Type(), visited))
+		return code.Add(typeToJen(t.Results().At(0).Type()))
 	}
 
-	return code.Add(tupleToJen(t.Results(), false, visited))
+	return code.Add(tupleToJen(t.Results(), false))
 }